@@ -0,0 +1,246 @@
+package sqltoken
+
+import (
+	"strings"
+)
+
+// FormatOpts control Format
+type FormatOpts struct {
+	// UppercaseKeywords changes keywords to upper case
+	UppercaseKeywords bool
+	// IndentWidth, if positive, puts the contents of each ( )
+	// group that holds a subquery or a list on new lines, indented by
+	// IndentWidth spaces for each level of nesting.  Other groups,
+	// like count(*), are left on one line.
+	IndentWidth int
+}
+
+// majorKeywords start a new line in Format
+var majorKeywords = map[string]struct{}{
+	"SELECT": {},
+	"FROM":   {},
+	"WHERE":  {},
+	"GROUP":  {},
+	"HAVING": {},
+	"ORDER":  {},
+	"LIMIT":  {},
+	"UNION":  {},
+	"VALUES": {},
+	"SET":    {},
+}
+
+// Format is a best-effort pretty printer.  It works from ts.Strip()
+// so comments are removed and whitespace is normalized.  There is no
+// parse: it just starts a new line before major keywords like SELECT,
+// FROM, and WHERE, and, with IndentWidth, indents ( ) groups that
+// hold a major keyword or a comma.
+func (ts Tokens) Format(opts FormatOpts) string {
+	var b strings.Builder
+	var depth int
+	var space, newline bool
+	stripped := ts.Strip()
+	var indented map[[2]int]bool
+	if opts.IndentWidth > 0 {
+		indented = indentedGroups(stripped)
+	}
+	// one entry for each open (: true if it is indented
+	var open []bool
+	emit := func(text string) {
+		if b.Len() > 0 {
+			if newline {
+				b.WriteString("\n")
+				b.WriteString(strings.Repeat(" ", depth*opts.IndentWidth))
+			} else if space {
+				b.WriteString(" ")
+			}
+		}
+		space, newline = false, false
+		b.WriteString(text)
+	}
+	for i, t := range stripped {
+		// nolint:exhaustive
+		switch t.Type {
+		case Whitespace:
+			space = true
+		case Word:
+			text := t.Text
+			if isKeyword(text) {
+				upper := strings.ToUpper(text)
+				if opts.UppercaseKeywords {
+					text = upper
+				}
+				if _, ok := majorKeywords[upper]; ok {
+					newline = true
+				}
+			}
+			emit(text)
+		case Punctuation:
+			if opts.IndentWidth <= 0 {
+				emit(t.Text)
+				continue
+			}
+			start := 0
+			for j := 0; j < len(t.Text); j++ {
+				switch t.Text[j] {
+				case '(':
+					if j+1 < len(t.Text) && t.Text[j+1] == ')' {
+						// ()
+						j++
+						continue
+					}
+					open = append(open, indented[[2]int{i, j}])
+					if !open[len(open)-1] {
+						continue
+					}
+					emit(t.Text[start : j+1])
+					depth++
+					newline = true
+					start = j + 1
+				case ')':
+					if len(open) == 0 {
+						continue
+					}
+					wasIndented := open[len(open)-1]
+					open = open[:len(open)-1]
+					if !wasIndented {
+						continue
+					}
+					if j > start {
+						emit(t.Text[start:j])
+					}
+					depth--
+					newline = true
+					emit(")")
+					start = j + 1
+				}
+			}
+			if start < len(t.Text) {
+				emit(t.Text[start:])
+			}
+		default:
+			emit(t.Text)
+		}
+	}
+	return b.String()
+}
+
+// indentedGroups returns the ( in ts, by token index and offset in
+// the token text, whose group holds a major keyword or a comma
+func indentedGroups(ts Tokens) map[[2]int]bool {
+	r := make(map[[2]int]bool)
+	var open [][2]int
+	for i, t := range ts {
+		// nolint:exhaustive
+		switch t.Type {
+		case Word:
+			if len(open) > 0 && isKeyword(t.Text) {
+				if _, ok := majorKeywords[strings.ToUpper(t.Text)]; ok {
+					r[open[len(open)-1]] = true
+				}
+			}
+		case Punctuation:
+			for j := 0; j < len(t.Text); j++ {
+				switch t.Text[j] {
+				case '(':
+					open = append(open, [2]int{i, j})
+				case ')':
+					if len(open) > 0 {
+						open = open[:len(open)-1]
+					}
+				case ',':
+					if len(open) > 0 {
+						r[open[len(open)-1]] = true
+					}
+				}
+			}
+		}
+	}
+	return r
+}
+
+// IndentParens returns a copy of ts where a newline and width spaces
+// of indentation follow each top-level ( and a newline comes before
+// the matching ).  Nested groups and () are left alone.  Any existing
+// whitespace just inside the parenthesis is replaced.  Unlike Format,
+// comments are kept and only whitespace is added or removed.
+func (ts Tokens) IndentParens(width int) Tokens {
+	c := make(Tokens, 0, len(ts))
+	var depth int
+	var skipSpace bool
+	indent := Token{Type: Whitespace, Text: "\n" + strings.Repeat(" ", width)}
+	for _, t := range ts {
+		if t.Type == Whitespace && skipSpace {
+			continue
+		}
+		skipSpace = false
+		if t.Type != Punctuation {
+			c = append(c, t)
+			continue
+		}
+		start := 0
+		for j := 0; j < len(t.Text); j++ {
+			switch t.Text[j] {
+			case '(':
+				if j+1 < len(t.Text) && t.Text[j+1] == ')' {
+					// ()
+					j++
+					continue
+				}
+				depth++
+				if depth == 1 {
+					c = append(c, Token{Type: Punctuation, Text: t.Text[start : j+1]}, indent)
+					start = j + 1
+					skipSpace = true
+				}
+			case ')':
+				if depth == 0 {
+					continue
+				}
+				depth--
+				if depth == 0 {
+					if j > start {
+						c = append(c, Token{Type: Punctuation, Text: t.Text[start:j]})
+					}
+					for len(c) > 0 && c[len(c)-1].Type == Whitespace {
+						c = c[:len(c)-1]
+					}
+					c = append(c, Token{Type: Whitespace, Text: "\n"})
+					start = j
+					skipSpace = false
+				}
+			}
+		}
+		if start < len(t.Text) {
+			c = append(c, Token{Type: Punctuation, Text: t.Text[start:]})
+			skipSpace = false
+		}
+	}
+	return c
+}
+
+// SingleLine returns ts as one line for logging.  Each run of
+// whitespace and comments becomes a single space and the result is
+// trimmed.  Unlike Strip, literals and other tokens are left
+// byte-exact, so a newline inside a string is kept, and semicolons
+// are not removed.
+func (ts Tokens) SingleLine() string {
+	var b strings.Builder
+	b.Grow(ts.ByteLen())
+	var space bool
+	for _, t := range ts {
+		// nolint:exhaustive
+		switch t.Type {
+		case Whitespace, Comment:
+			space = true
+			continue
+		case BOM:
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteString(t.Text)
+	}
+	return b.String()
+}
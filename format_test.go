@@ -0,0 +1,114 @@
+package sqltoken
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		input string
+		opts  FormatOpts
+		want  string
+	}{
+		{
+			input: "select a,b from t where x=1",
+			opts:  FormatOpts{},
+			want:  "select a,b\nfrom t\nwhere x=1",
+		},
+		{
+			input: "select a,b from t where x=1",
+			opts:  FormatOpts{UppercaseKeywords: true},
+			want:  "SELECT a,b\nFROM t\nWHERE x=1",
+		},
+		{
+			input: "  select a, 'from' /* where */ from t -- c\n",
+			opts:  FormatOpts{UppercaseKeywords: true},
+			want:  "SELECT a, 'from'\nFROM t",
+		},
+		{
+			input: "select a from t where x in (select y from u where z = f()) and w=1",
+			opts:  FormatOpts{UppercaseKeywords: true, IndentWidth: 2},
+			want:  "SELECT a\nFROM t\nWHERE x IN (\n  SELECT y\n  FROM u\n  WHERE z = f()\n) AND w=1",
+		},
+		{
+			input: "select count(*) from t where a=(1)",
+			opts:  FormatOpts{IndentWidth: 4},
+			want:  "select count(*)\nfrom t\nwhere a=(1)",
+		},
+		{
+			input: "select f(g(x), (1)) from t where a in (1,2)",
+			opts:  FormatOpts{IndentWidth: 2},
+			want:  "select f(\n  g(x), (1)\n)\nfrom t\nwhere a in (\n  1,2\n)",
+		},
+	}
+	for _, tc := range cases {
+		got := TokenizeMySQL(tc.input).Format(tc.opts)
+		require.Equal(t, tc.want, got, tc.input)
+	}
+}
+
+func TestIndentParens(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "f(a, g(b, c))",
+			want:  "f(\n  a, g(b, c)\n)",
+		},
+		{
+			input: "SELECT * FROM t WHERE x IN ( SELECT y /* c */ FROM u WHERE z = f() ) AND (a OR b)",
+			want:  "SELECT * FROM t WHERE x IN (\n  SELECT y /* c */ FROM u WHERE z = f()\n) AND (\n  a OR b\n)",
+		},
+		{
+			input: "SELECT f(), ((1)), 2)",
+			want:  "SELECT f(), (\n  (1)\n), 2)",
+		},
+	}
+	withoutSpace := func(ts Tokens) string {
+		var b strings.Builder
+		for _, t := range ts {
+			if t.Type != Whitespace {
+				b.WriteString(t.Text)
+			}
+		}
+		return b.String()
+	}
+	for _, tc := range cases {
+		ts := TokenizeMySQL(tc.input)
+		got := ts.IndentParens(2)
+		require.Equal(t, tc.want, got.String(), tc.input)
+		require.Equal(t, tc.input, ts.String(), "receiver unchanged")
+		require.Equal(t, withoutSpace(ts), withoutSpace(got), "only whitespace changed")
+	}
+}
+
+func TestSingleLine(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "\n  SELECT a, -- first\n\tb\n/* c */ FROM t\nWHERE s = 'multi\nline' ;\n",
+			want:  "SELECT a, b FROM t WHERE s = 'multi\nline' ;",
+		},
+		{
+			input: "SELECT 1;\nSELECT 2;",
+			want:  "SELECT 1; SELECT 2;",
+		},
+		{
+			input: "a/**/b",
+			want:  "a b",
+		},
+		{
+			input: " -- only a comment\n",
+			want:  "",
+		},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.want, TokenizeMySQL(tc.input).SingleLine(), tc.input)
+	}
+}
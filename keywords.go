@@ -0,0 +1,110 @@
+package sqltoken
+
+import (
+	"strings"
+)
+
+// keywords are the reserved words that are shared by the supported
+// SQL dialects.  They are stored in upper case.
+var keywords = func() map[string]struct{} {
+	m := make(map[string]struct{})
+	for _, w := range strings.Fields(`
+		ADD ALL ALTER AND ANY AS ASC BEGIN BETWEEN BY CALL CASCADE CASE
+		CAST CHECK COLLATE COLUMN COMMIT CONSTRAINT CREATE CROSS CURRENT
+		CURSOR DATABASE DECLARE DEFAULT DELETE DESC DISTINCT DROP ELSE END
+		ESCAPE EXCEPT EXISTS FALSE FETCH FOR FOREIGN FROM FULL FUNCTION
+		GRANT GROUP HAVING IF IN INDEX INNER INSERT INTERSECT INTO IS JOIN
+		KEY LEFT LIKE LIMIT NATURAL NOT NULL OFFSET ON OR ORDER OUTER
+		PRIMARY PROCEDURE REFERENCES RETURNING REVOKE RIGHT ROLLBACK SCHEMA
+		SELECT SET TABLE THEN TO TRANSACTION TRIGGER TRUE UNION UNIQUE
+		UPDATE USING VALUES VIEW WHEN WHERE WITH
+	`) {
+		m[w] = struct{}{}
+	}
+	return m
+}()
+
+func isKeyword(word string) bool {
+	_, ok := keywords[strings.ToUpper(word)]
+	return ok
+}
+
+// IsKeyword reports whether word (in any case) is a reserved word.
+// The list of keywords is currently common to all dialects.
+func (c Config) IsKeyword(word string) bool {
+	return isKeyword(word)
+}
+
+// UppercaseKeywords returns a copy of ts where Word tokens that are
+// keywords have been changed to upper case.  Identifiers, literals,
+// comments, and numbers are left alone.
+func (ts Tokens) UppercaseKeywords(cfg Config) Tokens {
+	c := make(Tokens, len(ts))
+	for i, t := range ts {
+		if t.Type == Word && cfg.IsKeyword(t.Text) {
+			t.Text = strings.ToUpper(t.Text)
+		}
+		c[i] = t
+	}
+	return c
+}
+
+// Span is the byte range [Start, End) of a Word within the string
+// that was tokenized
+type Span struct {
+	Start int
+	End   int
+	Word  string
+}
+
+// KeywordSpans returns the position of each Word token in ts that is
+// a keyword.  Positions are byte offsets into ts.String().
+func (ts Tokens) KeywordSpans(cfg Config) []Span {
+	var spans []Span
+	var pos int
+	for _, t := range ts {
+		if t.Type == Word && cfg.IsKeyword(t.Text) {
+			spans = append(spans, Span{
+				Start: pos,
+				End:   pos + len(t.Text),
+				Word:  t.Text,
+			})
+		}
+		pos += len(t.Text)
+	}
+	return spans
+}
+
+// QualifiedNames returns the names in ts: runs of Word and Identifier
+// tokens joined by "." with no whitespace, like schema.table or
+// "my schema".t.  Quoted parts keep their quotes.  Keywords that are
+// not part of a dotted name, and names followed directly by ( (which
+// are function calls), are skipped.  Names are returned in order and
+// may repeat.
+func (ts Tokens) QualifiedNames() []string {
+	var names []string
+	isPart := func(i int) bool {
+		return i < len(ts) && (ts[i].Type == Word || ts[i].Type == Identifier)
+	}
+	for i := 0; i < len(ts); i++ {
+		if !isPart(i) {
+			continue
+		}
+		name := ts[i].Text
+		end := i + 1
+		for end+1 < len(ts) && ts[end].Type == Punctuation && ts[end].Text == "." && isPart(end+1) {
+			name += "." + ts[end+1].Text
+			end += 2
+		}
+		single := end == i+1
+		i = end - 1
+		if single && ts[end-1].Type == Word && isKeyword(name) {
+			continue
+		}
+		if end < len(ts) && ts[end].Type == Punctuation && strings.HasPrefix(ts[end].Text, "(") {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,95 @@
+package sqltoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsKeyword(t *testing.T) {
+	c := MySQLConfig()
+	require.True(t, c.IsKeyword("select"))
+	require.True(t, c.IsKeyword("FROM"))
+	require.True(t, c.IsKeyword("Where"))
+	require.False(t, c.IsKeyword("t"))
+	require.False(t, c.IsKeyword(""))
+}
+
+func TestUppercaseKeywords(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "select * from t where x = 1",
+			want:  "SELECT * FROM t WHERE x = 1",
+		},
+		{
+			input: "select 'from', \"where\" /* select */ from `t` -- from\n",
+			want:  "SELECT 'from', \"where\" /* select */ FROM `t` -- from\n",
+		},
+		{
+			input: "select select_list, x1e5 from Tbl",
+			want:  "SELECT select_list, x1e5 FROM Tbl",
+		},
+	}
+	for _, tc := range cases {
+		ts := TokenizeMySQL(tc.input)
+		got := ts.UppercaseKeywords(MySQLConfig())
+		require.Equal(t, tc.want, got.String(), tc.input)
+		require.Equal(t, tc.input, ts.String(), "receiver unchanged")
+	}
+}
+
+func TestKeywordSpans(t *testing.T) {
+	input := "select 'from' ǝ, a FROM t -- where\n"
+	spans := TokenizeMySQL(input).KeywordSpans(MySQLConfig())
+	require.Equal(t, []Span{
+		{Start: 0, End: 6, Word: "select"},
+		{Start: 20, End: 24, Word: "FROM"},
+	}, spans)
+	for _, span := range spans {
+		require.Equal(t, span.Word, input[span.Start:span.End])
+	}
+	require.Nil(t, TokenizeMySQL("a, b").KeywordSpans(MySQLConfig()))
+}
+
+func TestQualifiedNames(t *testing.T) {
+	mySQLBackticks := MySQLConfig()
+	mySQLBackticks.NoticeBacktickIdentifiers = true
+	cases := []struct {
+		input  string
+		config Config
+		want   []string
+	}{
+		{
+			input:  "SELECT * FROM a.b.c",
+			config: MySQLConfig(),
+			want:   []string{"a.b.c"},
+		},
+		{
+			input:  "SELECT * FROM t1, s.t2",
+			config: MySQLConfig(),
+			want:   []string{"t1", "s.t2"},
+		},
+		{
+			input:  "SELECT count(*), s.f(x), t.* FROM `my db`.`t` JOIN u ON t.id = u.id WHERE a.b = 'c.d'",
+			config: mySQLBackticks,
+			want:   []string{"x", "t", "`my db`.`t`", "u", "t.id", "u.id", "a.b"},
+		},
+		{
+			input:  `SELECT "Col" FROM "my schema".t, s . t`,
+			config: ANSIConfig(),
+			want:   []string{`"Col"`, `"my schema".t`, "s", "t"},
+		},
+		{
+			input:  `SELECT x FROM [my db].[t]`,
+			config: SQLServerConfig(),
+			want:   []string{"x", "[my db].[t]"},
+		},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.want, Tokenize(tc.input, tc.config).QualifiedNames(), tc.input)
+	}
+	require.Nil(t, TokenizeMySQL("SELECT 1").QualifiedNames())
+}
@@ -0,0 +1,175 @@
+package sqltoken
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Param describes one parameter marker found by Params
+type Param struct {
+	// Index is the bind position of a positional parameter: QuestionMark
+	// markers are counted from 1 and $N and :N have Index N.  Named
+	// parameters have Index 0.
+	Index int
+	// Kind is the type of the marker token: QuestionMark, DollarNumber,
+	// ColonNumber, ColonWord, or AtWord
+	Kind TokenType
+	// Name is the name of a named parameter without the leading : or @
+	Name string
+	// TokenIndex is the position of the marker within the Tokens
+	TokenIndex int
+}
+
+// Params returns the parameter markers in ts in the order that they
+// appear.  Money constants, like SQL Server's $10 and $10.32, are
+// type Money rather than DollarNumber and so are not parameters.
+func (ts Tokens) Params() []Param {
+	var params []Param
+	var questionMarks int
+	for i, t := range ts {
+		// nolint:exhaustive
+		switch t.Type {
+		case QuestionMark:
+			questionMarks++
+			params = append(params, Param{
+				Index:      questionMarks,
+				Kind:       t.Type,
+				TokenIndex: i,
+			})
+		case DollarNumber:
+			n, ok := dollarParamIndex(t.Text)
+			if !ok {
+				continue
+			}
+			params = append(params, Param{
+				Index:      n,
+				Kind:       t.Type,
+				TokenIndex: i,
+			})
+		case ColonNumber:
+			n, _ := strconv.Atoi(t.Text[1:])
+			params = append(params, Param{
+				Index:      n,
+				Kind:       t.Type,
+				TokenIndex: i,
+			})
+		case ColonWord, AtWord:
+			params = append(params, Param{
+				Kind:       t.Type,
+				Name:       t.Text[1:],
+				TokenIndex: i,
+			})
+		}
+	}
+	return params
+}
+
+// ParamCount returns the number of parameter markers in ts.  It is
+// the same as len(ts.Params()) but does not allocate.
+func (ts Tokens) ParamCount() int {
+	var n int
+	for _, t := range ts {
+		// nolint:exhaustive
+		switch t.Type {
+		case QuestionMark, ColonNumber, ColonWord, AtWord:
+			n++
+		case DollarNumber:
+			if _, ok := dollarParamIndex(t.Text); ok {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// WithoutParams returns a copy of ts without the parameter markers
+// that Params finds.  Literals and other values are left alone.  The
+// tokens on either side of a removed marker are merged if Tokenize
+// would have merged them, so a = ? AND b becomes a =  AND b with one
+// Whitespace token between = and AND.
+func (ts Tokens) WithoutParams() Tokens {
+	params := ts.Params()
+	c := make(Tokens, 0, len(ts)-len(params))
+	var next int
+	for i, t := range ts {
+		if next < len(params) && params[next].TokenIndex == i {
+			next++
+			continue
+		}
+		if i > 0 && len(c) > 0 && next > 0 && params[next-1].TokenIndex == i-1 &&
+			c[len(c)-1].Type == t.Type && combineOkay(t.Type) {
+			c[len(c)-1].Text += t.Text
+			continue
+		}
+		c = append(c, t)
+	}
+	return c
+}
+
+// maxDollarNumberGap is the largest index that DollarNumberGaps
+// reports: the PostgreSQL protocol cannot bind more parameters
+const maxDollarNumberGap = 65535
+
+// DollarNumberGaps returns, in increasing order, the indexes between 1
+// and the largest $N in ts that are not used, so $1, $3 gives [2].
+// A statement whose $N parameters are contiguous returns nil.  Only
+// gaps up to 65535, the most parameters PostgreSQL can bind, are
+// reported so a huge $N cannot make the result huge.
+func (ts Tokens) DollarNumberGaps() []int {
+	var used []int
+	for _, t := range ts {
+		if t.Type != DollarNumber {
+			continue
+		}
+		if n, ok := dollarParamIndex(t.Text); ok && n > 0 {
+			used = append(used, n)
+		}
+	}
+	sort.Ints(used)
+	var gaps []int
+	want := 1
+	for _, n := range used {
+		for ; want < n && want <= maxDollarNumberGap; want++ {
+			gaps = append(gaps, want)
+		}
+		want = n + 1
+	}
+	return gaps
+}
+
+// dollarParamIndex returns N for a DollarNumber that is $N
+func dollarParamIndex(text string) (int, bool) {
+	n, err := strconv.Atoi(text[1:])
+	if err != nil || !isDigit(text[1]) {
+		return 0, false
+	}
+	return n, true
+}
+
+// SubstituteParams returns a copy of ts with each parameter marker
+// replaced by a value from args.  Named parameters are looked up by
+// Name (without the : or @) and positional parameters by their Index
+// ("1", "2", ...).  The values must already be quoted: they are
+// inserted as-is, as Number tokens if they parse as numbers and as
+// Literal tokens otherwise.  This is only safe for trusted values.  An
+// error is returned if a parameter has no value in args.
+func (ts Tokens) SubstituteParams(args map[string]string) (Tokens, error) {
+	c := ts.Copy()
+	for _, p := range ts.Params() {
+		key := p.Name
+		if p.Name == "" {
+			key = strconv.Itoa(p.Index)
+		}
+		v, ok := args[key]
+		if !ok {
+			return nil, fmt.Errorf("no value for parameter %s", ts[p.TokenIndex].Text)
+		}
+		t := Token{Type: Literal, Text: v}
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			t.Type = Number
+		}
+		c[p.TokenIndex] = t
+	}
+	return c, nil
+}
@@ -0,0 +1,165 @@
+package sqltoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParams(t *testing.T) {
+	cases := []struct {
+		input  string
+		config Config
+		want   []Param
+	}{
+		{
+			input:  "SELECT * FROM t WHERE a = ? AND b = '?' AND c = ?",
+			config: MySQLConfig(),
+			want: []Param{
+				{Index: 1, Kind: QuestionMark, TokenIndex: 14},
+				{Index: 2, Kind: QuestionMark, TokenIndex: 30},
+			},
+		},
+		{
+			input:  "SELECT $2, $1, $$ $3 $$, $2",
+			config: PostgreSQLConfig(),
+			want: []Param{
+				{Index: 2, Kind: DollarNumber, TokenIndex: 2},
+				{Index: 1, Kind: DollarNumber, TokenIndex: 5},
+				{Index: 2, Kind: DollarNumber, TokenIndex: 11},
+			},
+		},
+		{
+			input:  "SELECT * FROM t WHERE id = :id AND name = :name",
+			config: OracleConfig(),
+			want: []Param{
+				{Kind: ColonWord, Name: "id", TokenIndex: 14},
+				{Kind: ColonWord, Name: "name", TokenIndex: 22},
+			},
+		},
+		{
+			input:  "SELECT @id, $10.32, $-1, $10",
+			config: SQLServerConfig(),
+			want: []Param{
+				{Kind: AtWord, Name: "id", TokenIndex: 2},
+			},
+		},
+		{
+			input:  "SELECT :2, :name, :1 FROM dual",
+			config: OracleConfig(),
+			want: []Param{
+				{Index: 2, Kind: ColonNumber, TokenIndex: 2},
+				{Kind: ColonWord, Name: "name", TokenIndex: 5},
+				{Index: 1, Kind: ColonNumber, TokenIndex: 8},
+			},
+		},
+		{
+			input:  "SELECT 1",
+			config: MySQLConfig(),
+			want:   nil,
+		},
+	}
+	for _, tc := range cases {
+		ts := Tokenize(tc.input, tc.config)
+		got := ts.Params()
+		require.Equal(t, tc.want, got, tc.input)
+		require.Equal(t, len(got), ts.ParamCount(), tc.input)
+		for _, p := range got {
+			require.Equal(t, p.Kind, ts[p.TokenIndex].Type, tc.input)
+		}
+	}
+}
+
+func TestParamCount(t *testing.T) {
+	require.Equal(t, 2, TokenizeMySQL("SELECT ?, ?").ParamCount())
+	require.Equal(t, 3, TokenizePostgreSQL("SELECT $1, $2, $1").ParamCount())
+	require.Equal(t, 0, Tokenize("SELECT '?', `?` /* ? */", BigQueryConfig()).ParamCount())
+	require.Equal(t, 1, Tokenize("SELECT @a, @@ROWCOUNT, $10.32, $10", SQLServerConfig()).ParamCount())
+}
+
+func TestDollarNumberGaps(t *testing.T) {
+	cases := []struct {
+		input string
+		want  []int
+	}{
+		{input: "SELECT $1, $2, $3", want: nil},
+		{input: "SELECT $1, $3", want: []int{2}},
+		{input: "SELECT $3, $1, $3", want: []int{2}},
+		{input: "SELECT $4 WHERE x = $1", want: []int{2, 3}},
+		{input: "SELECT $2", want: []int{1}},
+		{input: "SELECT '$2', $$ $3 $$", want: nil},
+		{input: "SELECT 1", want: nil},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.want, TokenizePostgreSQL(tc.input).DollarNumberGaps(), tc.input)
+	}
+	require.Nil(t, Tokenize("SELECT $3, $10, $10.32", SQLServerConfig()).DollarNumberGaps(), "money is not a parameter")
+	gaps := TokenizePostgreSQL("SELECT $1, $2000000000, $99999999999999999999").DollarNumberGaps()
+	require.Len(t, gaps, 65534, "gaps are capped")
+	require.Equal(t, 2, gaps[0])
+	require.Equal(t, 65535, gaps[len(gaps)-1])
+}
+
+func TestWithoutParams(t *testing.T) {
+	cases := []struct {
+		input  string
+		config Config
+		want   string
+	}{
+		{
+			input:  "SELECT * FROM t WHERE a = ? AND b IN (?, ?)",
+			config: MySQLConfig(),
+			want:   "SELECT * FROM t WHERE a =  AND b IN (, )",
+		},
+		{
+			input:  "SELECT $1, '$2' FROM t WHERE a = $2",
+			config: PostgreSQLConfig(),
+			want:   "SELECT , '$2' FROM t WHERE a = ",
+		},
+		{
+			input:  "SELECT :name, :1 FROM dual",
+			config: OracleConfig(),
+			want:   "SELECT ,  FROM dual",
+		},
+		{
+			input:  "SELECT @id, $10.32, $10",
+			config: SQLServerConfig(),
+			want:   "SELECT , $10.32, $10",
+		},
+	}
+	for _, tc := range cases {
+		ts := Tokenize(tc.input, tc.config)
+		got := ts.WithoutParams()
+		require.Equal(t, tc.want, got.String(), tc.input)
+		require.Zero(t, got.ParamCount(), tc.input)
+		require.True(t, Tokenize(got.String(), tc.config).Equal(got), tc.input)
+	}
+}
+
+func TestSubstituteParams(t *testing.T) {
+	ts := Tokenize("SELECT * FROM t WHERE id = :id AND name = :name OR alt = :name", OracleConfig())
+	got, err := ts.SubstituteParams(map[string]string{
+		"id":   "10",
+		"name": "'O''Brien'",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM t WHERE id = 10 AND name = 'O''Brien' OR alt = 'O''Brien'", got.String())
+	require.Equal(t, Number, got[14].Type)
+	require.Equal(t, Literal, got[22].Type)
+	require.Equal(t, ColonWord, ts[14].Type, "receiver unchanged")
+
+	_, err = Tokenize("SELECT :id, :missing", OracleConfig()).SubstituteParams(map[string]string{"id": "1"})
+	require.EqualError(t, err, "no value for parameter :missing")
+
+	got, err = TokenizeMySQL("SELECT ?, ?").SubstituteParams(map[string]string{"1": "'a'", "2": "2.5"})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT 'a', 2.5", got.String())
+
+	got, err = TokenizePostgreSQL("SELECT $2, $1").SubstituteParams(map[string]string{"1": "'a'", "2": "'b'"})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT 'b', 'a'", got.String())
+
+	got, err = Tokenize("SELECT @a, $10", SQLServerConfig()).SubstituteParams(map[string]string{"a": "1"})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT 1, $10", got.String())
+}
@@ -25,13 +25,23 @@ const (
 	Semicolon
 	Punctuation
 	Word
-	Other // control characters and other non-printables
+	Other                // control characters and other non-printables
+	Operator             // used in PostgreSQL, see NoticePgOperators
+	BOM                  // UTF-8 byte order mark at the start of the input
+	Hint                 // used in Oracle, see NoticeOptimizerHints
+	TypedLiteral         // DATE '2020-01-01', see NoticeTypedLiterals
+	CopyData             // inline data after COPY ... FROM STDIN, see NoticeCopyData
+	SystemVariable       // used in SQL Server, see NoticeSystemVariables
+	ColonNumber          // used in Oracle substitution, see NoticeColonNumber
+	SubstitutionVariable // used in Oracle SQL*Plus, see NoticeAmpersandSubstitution
+	Truncated            // the rest of the input after MaxTokens tokens
+	Money                // used in SQL Server, see NoticeMoneyConstants
 )
 
 func combineOkay(t TokenType) bool {
 	// nolint:exhaustive
 	switch t {
-	case Number, QuestionMark, DollarNumber, ColonWord:
+	case Number, QuestionMark, DollarNumber, ColonWord, ColonNumber, Operator, SubstitutionVariable, Money:
 		return false
 	}
 	return true
@@ -40,6 +50,14 @@ func combineOkay(t TokenType) bool {
 type Token struct {
 	Type TokenType
 	Text string
+
+	// Unterminated is set when the input ended inside a string,
+	// quoted identifier, or block comment
+	Unterminated bool
+
+	// Meta is for use by callers to annotate tokens.  Tokenize always
+	// leaves it nil.  See SetMeta and GetMeta.
+	Meta map[string]any
 }
 
 // Config specifies the behavior of Tokenize as relates to behavior
@@ -51,12 +69,19 @@ type Config struct {
 	// Tokenize $7 as type DollarNumber (PostgreSQL)
 	NoticeDollarNumber bool
 
-	// Tokenize :word as type ColonWord (sqlx, Oracle)
+	// Tokenize :word as type ColonWord (sqlx, Oracle).  This can be
+	// combined with NoticeAtWord to accept both :name and @name
+	// parameters.
 	NoticeColonWord bool
 
 	// Tokenize :word with unicode as ColonWord (sqlx)
 	ColonWordIncludesUnicode bool
 
+	// ColonWordNotInBrackets : inside [ ] is Punctuation even when
+	// NoticeColonWord is set so that array slices like a[1:n] are not
+	// mistaken for :n (PostgreSQL)
+	ColonWordNotInBrackets bool
+
 	// Tokenize # as type comment (MySQL)
 	NoticeHashComment bool
 
@@ -84,29 +109,233 @@ type Config struct {
 	// NoticeTypedNumbers nn.nnEnn[fFdD] (Oracle)
 	NoticeTypedNumbers bool
 
-	// NoticeMoneyConstants $10 $10.32 (SQL Server)
+	// NoticeMoneyConstants $10 $10.32 $-10 as type Money (SQL
+	// Server).  In -$10 the - is Punctuation, as it is for -10.  A $
+	// that is not followed by a digit is Punctuation unless it
+	// continues an identifier: $foo is Punctuation then Word but, with
+	// NoticeIdentifiers, a$b is an Identifier.  NoticeDollarQuotes and
+	// NoticeDollarNumber take precedence.
 	NoticeMoneyConstants bool
 
-	// NoticeAtWord @foo (SQL Server)
+	// NoticeAtWord @foo as type AtWord (SQL Server).  An @ that is
+	// not followed by a letter is Punctuation and @@foo is
+	// Punctuation @@ then Word foo, so neither is a parameter.  With
+	// NoticeIdentifiers, @foo is still an AtWord but @fo$o, @@foo,
+	// and @1 are Identifiers.
 	NoticeAtWord bool
 
 	// NoticeAtIdentifiers _baz @fo$o @@b#ar #foo ##b@ar(SQL Server)
 	NoticeIdentifiers bool
+
+	// NoticePgOperators <<>> @@ || => as type Operator: runs of
+	// + - * / < > = ~ ! @ # % ^ & | ` ? are grouped using
+	// PostgreSQL's rules rather than merged as Punctuation (PostgreSQL)
+	NoticePgOperators bool
+
+	// NoticeOptimizerHints /*+ FULL(t) */ and --+ FULL(t) as type
+	// Hint rather than Comment (Oracle)
+	NoticeOptimizerHints bool
+
+	// NoticeBackslashG \G as type Semicolon (mysql client)
+	NoticeBackslashG bool
+
+	// NoBackslashEscapes 'foo\' is a complete string: only '' can
+	// be used to embed a quote (ANSI, SAP HANA)
+	NoBackslashEscapes bool
+
+	// AnsiQuotes "foo" is an Identifier rather than a Literal and
+	// "" embeds a double quote (ANSI, SAP HANA, MySQL with the
+	// ANSI_QUOTES SQL mode)
+	AnsiQuotes bool
+
+	// NoticeCurlyComments { comment } as type Comment.  Comments
+	// do not nest: the first } ends the comment (Informix)
+	NoticeCurlyComments bool
+
+	// NestedComments /* a /* b */ c */ is one Comment: each /*
+	// needs its own */ (PostgreSQL, CockroachDB)
+	NestedComments bool
+
+	// NoticeDoubleSlashComment // comment to end of line as type
+	// Comment (Snowflake, some Transact-SQL tools)
+	NoticeDoubleSlashComment bool
+
+	// NoticeTempTableNames #temp ##global as type Identifier even
+	// when NoticeIdentifiers is off.  NoticeHashComment and
+	// NoticeIdentifiers take precedence (SQL Server)
+	NoticeTempTableNames bool
+
+	// NoticeTypedLiterals DATE '2020-01-01' TIME '...' TIMESTAMP '...'
+	// INTERVAL '1' as a single TypedLiteral token that includes the
+	// keyword, any whitespace, and the Literal (Presto, ANSI)
+	NoticeTypedLiterals bool
+
+	// IdentifierExtraChars are ASCII characters, beyond letters,
+	// digits, and _, that may continue (but not start) a Word: with
+	// "$", foo$bar is one Word.  When NoticeIdentifiers is set, a #
+	// @ or $ inside a word starts an Identifier instead.
+	IdentifierExtraChars string
+
+	// EscapedQuestionMark ?? as type Punctuation: an escaped ? that
+	// is not a parameter.  Only applies with NoticeQuestionMark (JDBC)
+	EscapedQuestionMark bool
+
+	// SeparatePunctuation stops adjacent Punctuation from being
+	// combined into one token: =@ is = and @ rather than =@.
+	// Normally the only token types that are not combined are the
+	// ones listed in combineOkay.  Punctuation that is lexed as a
+	// unit, like :: with NoticeColonWord, is not split.
+	SeparatePunctuation bool
+
+	// PunctuationRunLimit, if positive, is the maximum length in bytes
+	// of a Punctuation token.  Longer runs, whether they come from
+	// combining adjacent Punctuation or were lexed as a unit, are
+	// split into several tokens.  A multi-byte character is never
+	// split, so a single character may be longer than the limit.
+	PunctuationRunLimit int
+
+	// ControlCharsAreOther control characters like \x00 as type Other
+	// rather than Whitespace.  Spaces, tabs, newlines, \b, \v, \f, and
+	// other unicode spaces are still Whitespace.
+	ControlCharsAreOther bool
+
+	// NoticeBracketIdentifiers [my table] as type Identifier and ]]
+	// embeds a ] (SQL Server)
+	NoticeBracketIdentifiers bool
+
+	// DashDashRequiresSpace -- only starts a comment when it is followed
+	// by whitespace or the end of the input, as the SQL standard says.
+	// Otherwise -- is Punctuation: --c is -- then c.  With
+	// NoticePgOperators, --c is lexed as operators instead, and with
+	// NoticeOptimizerHints, --+ still starts a Hint.
+	DashDashRequiresSpace bool
+
+	// NoticeCopyData the lines that follow COPY ... FROM STDIN; up to
+	// and including a line that is only \. as a single CopyData token.
+	// The data starts on the line after the ; and, since it comes after
+	// the Semicolon, CmdSplit puts it at the start of the next command
+	// (PostgreSQL psql scripts)
+	NoticeCopyData bool
+
+	// NoticeSystemVariables @@ROWCOUNT as type SystemVariable rather
+	// than Identifier.  Only applies with NoticeAtWord.  With
+	// NoticeIdentifiers, @@b#ar is still an Identifier (SQL Server)
+	NoticeSystemVariables bool
+
+	// ASCIIOnlyIdentifiers a Word is made of only ASCII letters,
+	// digits, and _.  Other letters, like the è in eè, are type Other.
+	// Unicode digits are still Numbers.
+	ASCIIOnlyIdentifiers bool
+
+	// UnterminatedDollarIsLiteral a $$ or $tag$ that is never closed
+	// starts an Unterminated Literal that runs to the end of the input.
+	// Otherwise the opening $ is Punctuation.  Only applies with
+	// NoticeDollarQuotes (PostgreSQL)
+	UnterminatedDollarIsLiteral bool
+
+	// HashCommentRequiresLineStartOrSpace # only starts a comment at
+	// the start of the input, the start of a line, or after whitespace.
+	// Otherwise # is handled as if NoticeHashComment were off: a#c is
+	// a then # then c.  Only applies with NoticeHashComment (MySQL)
+	HashCommentRequiresLineStartOrSpace bool
+
+	// CurrencyPrefixes are the non-ASCII symbols, in addition to $,
+	// that start money constants: with "£€", £10 and €10.50 are type
+	// Money.  Only applies with NoticeMoneyConstants (SQL Server)
+	CurrencyPrefixes string
+
+	// NoticeBacktickIdentifiers `my table` as type Identifier and ``
+	// embeds a ` (MySQL, BigQuery).  MySQLConfig leaves it off so
+	// backticks stay Punctuation unless it is set.
+	NoticeBacktickIdentifiers bool
+
+	// BackslashLineContinuation a \ at the end of a line that is in a
+	// -- # or // comment continues the comment onto the next line
+	BackslashLineContinuation bool
+
+	// StandardConformingStrings \ is an ordinary character in '...'
+	// strings, as if NoBackslashEscapes were set, but E'...' strings
+	// are recognized and \ is an escape in them (PostgreSQL)
+	StandardConformingStrings bool
+
+	// NoticeColonNumber :1 as type ColonNumber.  Only applies with
+	// NoticeColonWord (Oracle)
+	NoticeColonNumber bool
+
+	// NoticeHexFloat 0x1.8p3 as a single Number: a hex number may
+	// have a . fraction and a p binary exponent.  Only applies with
+	// NoticeHexNumbers.
+	NoticeHexFloat bool
+
+	// NoticeDigitSeparators 1_000 and 0x_FF as a single Number: a
+	// single _ may appear between the digits of a number (PostgreSQL)
+	NoticeDigitSeparators bool
+
+	// NoticeAmpersandSubstitution &name and &&name as type
+	// SubstitutionVariable.  A & that is not followed by a word
+	// character is left alone, but a&b is Word a then
+	// SubstitutionVariable &b.  (Oracle SQL*Plus)
+	NoticeAmpersandSubstitution bool
+
+	// MaxTokens, if positive, limits the number of tokens that
+	// Tokenize produces.  Once the limit is reached, tokenizing
+	// stops and the rest of the input is returned, unexamined, as a
+	// single Truncated token.  This bounds the memory used for
+	// untrusted input.
+	MaxTokens int
 }
 
 type Tokens []Token
 
 type TokensList []Tokens
 
+// WithSeparatePunctuation returns a copy of c with SeparatePunctuation
+// set.  This is useful for tools that look at individual operators
+// in dialects that do not have NoticePgOperators.  It is not the
+// default for any preset because merged punctuation has long been
+// the behavior of Tokenize.
+func (c Config) WithSeparatePunctuation() Config {
+	c.SeparatePunctuation = true
+	return c
+}
+
+// WithNoticeQuestionMark returns a copy of c with NoticeQuestionMark
+// set.  When combined with NoticePgOperators, a bare ?, like a = ?,
+// is a QuestionMark while a ? followed by |, &, -, or #, like
+// data ?| array['a'], starts an Operator.  The PostgreSQL ? operator
+// itself (data ? 'k') is then a QuestionMark.  This is for tools that
+// accept ? placeholders for PostgreSQL and convert them to $N.
+func (c Config) WithNoticeQuestionMark() Config {
+	c.NoticeQuestionMark = true
+	return c
+}
+
+// ANSIConfig returns a conservative parsing configuration that follows
+// standard SQL: -- and /* */ comments, '...' strings where only a
+// doubled quote embeds a quote, and "..." delimited identifiers.  No vendor
+// extensions (including ? parameters) are enabled.  This is the
+// recommended starting point when the SQL dialect is unknown.
+func ANSIConfig() Config {
+	return Config{
+		NoBackslashEscapes: true,
+		AnsiQuotes:         true,
+	}
+}
+
 // OracleConfig returns a parsing configuration that is appropriate
-// for parsing Oracle's SQL
+// for parsing Oracle's SQL.  Since Oracle SQL has no & operator, it
+// includes NoticeAmpersandSubstitution so &name is a
+// SubstitutionVariable even without surrounding spaces.
 func OracleConfig() Config {
 	// https://docs.oracle.com/en/database/oracle/oracle-database/19/sqlrf/Literals.html
 	return Config{
-		NoticeNotionalStrings:    true,
-		NoticeDeliminatedStrings: true,
-		NoticeTypedNumbers:       true,
-		NoticeColonWord:          true,
+		NoticeNotionalStrings:       true,
+		NoticeDeliminatedStrings:    true,
+		NoticeTypedNumbers:          true,
+		NoticeColonWord:             true,
+		NoticeOptimizerHints:        true,
+		NoticeColonNumber:           true,
+		NoticeAmpersandSubstitution: true,
 	}
 }
 
@@ -114,11 +343,13 @@ func OracleConfig() Config {
 // for parsing SQLServer's SQL
 func SQLServerConfig() Config {
 	return Config{
-		NoticeNotionalStrings: true,
-		NoticeHexNumbers:      true,
-		NoticeMoneyConstants:  true,
-		NoticeAtWord:          true,
-		NoticeIdentifiers:     true,
+		NoticeNotionalStrings:    true,
+		NoticeHexNumbers:         true,
+		NoticeMoneyConstants:     true,
+		NoticeAtWord:             true,
+		NoticeIdentifiers:        true,
+		NoticeBracketIdentifiers: true,
+		NoticeSystemVariables:    true,
 	}
 }
 
@@ -135,12 +366,76 @@ func MySQLConfig() Config {
 }
 
 // PostgreSQL returns a parsing configuration that is appropriate
-// for parsing PostgreSQL and CockroachDB SQL.
+// for parsing PostgreSQL SQL.  See also CockroachDBConfig.
 func PostgreSQLConfig() Config {
 	return Config{
-		NoticeDollarNumber: true,
-		NoticeDollarQuotes: true,
-		NoticeUAmpPrefix:   true,
+		NoticeDollarNumber:        true,
+		NoticeDollarQuotes:        true,
+		NoticeUAmpPrefix:          true,
+		NoticePgOperators:         true,
+		StandardConformingStrings: true,
+		NestedComments:            true,
+	}
+}
+
+// CockroachDBConfig returns a parsing configuration that is appropriate
+// for parsing CockroachDB SQL.  It starts out close to PostgreSQLConfig
+// but CockroachDB has x'...' byte literals and does not have
+// U&'...' strings.
+func CockroachDBConfig() Config {
+	return Config{
+		NoticeDollarNumber:        true,
+		NoticeDollarQuotes:        true,
+		NoticePgOperators:         true,
+		NoticeHexNumbers:          true,
+		StandardConformingStrings: true,
+		NestedComments:            true,
+	}
+}
+
+// HANAConfig returns a parsing configuration that is appropriate
+// for parsing SAP HANA SQL.
+func HANAConfig() Config {
+	return Config{
+		NoticeQuestionMark: true,
+		NoticeColonWord:    true,
+		NoticeHexNumbers:   true,
+		NoBackslashEscapes: true,
+		AnsiQuotes:         true,
+	}
+}
+
+// InformixConfig returns a parsing configuration that is appropriate
+// for parsing Informix SQL.
+func InformixConfig() Config {
+	return Config{
+		NoticeQuestionMark:  true,
+		NoticeCurlyComments: true,
+	}
+}
+
+// H2Config returns a parsing configuration that is appropriate
+// for parsing H2 and HSQLDB SQL.
+func H2Config() Config {
+	return Config{
+		NoticeQuestionMark:       true,
+		NoticeColonWord:          true,
+		NoticeDollarQuotes:       true,
+		NoticeDoubleSlashComment: true,
+		NoBackslashEscapes:       true,
+		AnsiQuotes:               true,
+	}
+}
+
+// BigQueryConfig returns a parsing configuration that is appropriate
+// for parsing Google BigQuery SQL.  A whole path may be quoted at once
+// so `project.dataset.table` is a single Identifier.
+func BigQueryConfig() Config {
+	return Config{
+		NoticeQuestionMark:        true,
+		NoticeHashComment:         true,
+		NoticeAtWord:              true,
+		NoticeBacktickIdentifiers: true,
 	}
 }
 
@@ -150,7 +445,7 @@ func TokenizeMySQL(s string) Tokens {
 	return Tokenize(s, MySQLConfig())
 }
 
-// TokenizePostgreSQL breaks up PostgreSQL / CockroachDB SQL strings into
+// TokenizePostgreSQL breaks up PostgreSQL SQL strings into
 // Token objects.
 func TokenizePostgreSQL(s string) Tokens {
 	return Tokenize(s, PostgreSQLConfig())
@@ -158,8 +453,10 @@ func TokenizePostgreSQL(s string) Tokens {
 
 const debug = false
 
+const utf8BOM = "\xEF\xBB\xBF"
+
 // Tokenize breaks up SQL strings into Token objects.  No attempt is made
-// to break successive punctuation.
+// to break successive punctuation unless NoticePgOperators is set.
 func Tokenize(s string, config Config) Tokens {
 	if len(s) == 0 {
 		return []Token{}
@@ -170,6 +467,11 @@ func Tokenize(s string, config Config) Tokens {
 	var firstDollarEnd int
 	var runeDelim rune
 	var charDelim byte
+	commentType := Comment
+	var commentDepth int
+	var bracketDepth int
+	var copyPending bool
+	var escapeString bool
 
 	// Why is this written with Goto you might ask?  It's written
 	// with goto because RE2 can't handle complex regex and PCRE
@@ -178,14 +480,27 @@ func Tokenize(s string, config Config) Tokens {
 	// just a way to do goto that's lower performance.  Might as
 	// well do goto the natural way.
 
-	token := func(t TokenType) {
+	var token func(t TokenType)
+	token = func(t TokenType) {
 		if debug {
 			fmt.Printf("> %s: {%s}\n", t, s[tokenStart:i])
 		}
 		if i-tokenStart == 0 {
 			return
 		}
-		if len(tokens) > 0 && tokens[len(tokens)-1].Type == t && combineOkay(t) {
+		if t == Punctuation && config.PunctuationRunLimit > 0 && i-tokenStart > config.PunctuationRunLimit {
+			// ::::: is lexed in one go but is still split
+			if n := runLimitCut(s[tokenStart:i], config.PunctuationRunLimit); n < i-tokenStart {
+				end := i
+				i = tokenStart + n
+				token(t)
+				i = end
+				token(t)
+				return
+			}
+		}
+		if len(tokens) > 0 && tokens[len(tokens)-1].Type == t && combineOkay(t) &&
+			(t != Punctuation || config.punctuationCombineOkay(len(tokens[len(tokens)-1].Text)+i-tokenStart)) {
 			tokens[len(tokens)-1].Text = s[tokenStart-len(tokens[len(tokens)-1].Text) : i]
 		} else {
 			tokens = append(tokens, Token{
@@ -196,60 +511,186 @@ func Tokenize(s string, config Config) Tokens {
 		tokenStart = i
 	}
 
+	// unterminated is used instead of token when the input ran out
+	// before the closing delimiter
+	unterminated := func(t TokenType) {
+		if i == tokenStart {
+			// nothing to flag: COPY ... FROM STDIN; at the end
+			return
+		}
+		token(t)
+		tokens[len(tokens)-1].Unterminated = true
+	}
+
+	if strings.HasPrefix(s, utf8BOM) {
+		i = len(utf8BOM)
+		token(BOM)
+	}
+
 BaseState:
 	for i < len(s) {
+		if config.MaxTokens > 0 && len(tokens) > config.MaxTokens {
+			goto Done
+		}
 		c := s[i]
 		i++
 		switch c {
 		case '/':
 			if i < len(s) && s[i] == '*' {
+				i++
+				if config.NoticeOptimizerHints && i < len(s) && s[i] == '+' {
+					commentType = Hint
+				}
 				goto CStyleComment
 			}
+			if config.NoticeDoubleSlashComment && i < len(s) && s[i] == '/' {
+				goto SkipToEOL
+			}
+			if config.NoticePgOperators {
+				goto PgOperator
+			}
 			token(Punctuation)
 		case '\'':
 			goto SingleQuoteString
 		case '"':
+			if config.AnsiQuotes {
+				goto QuotedIdentifier
+			}
 			goto DoubleQuoteString
 		case '-':
+			if config.DashDashRequiresSpace && i+1 < len(s) && s[i] == '-' && !isSpaceByte(s[i+1]) &&
+				!(config.NoticeOptimizerHints && s[i+1] == '+') {
+				// --c
+				if config.NoticePgOperators {
+					goto PgOperator
+				}
+				i++
+				token(Punctuation)
+				continue
+			}
 			if i < len(s) && s[i] == '-' {
+				if config.NoticeOptimizerHints && i+1 < len(s) && s[i+1] == '+' {
+					commentType = Hint
+				}
 				goto SkipToEOL
 			}
+			if config.NoticePgOperators {
+				goto PgOperator
+			}
 			token(Punctuation)
 		case '#':
-			if config.NoticeHashComment {
+			if config.NoticeHashComment && (!config.HashCommentRequiresLineStartOrSpace || len(tokens) == 0 ||
+				tokens[len(tokens)-1].Type == Whitespace || tokens[len(tokens)-1].Type == BOM ||
+				strings.HasSuffix(tokens[len(tokens)-1].Text, "\n")) {
 				goto SkipToEOL
 			}
 			if config.NoticeIdentifiers {
 				goto Identifier
 			}
+			if config.NoticeTempTableNames {
+				goto TempTableName
+			}
+			if config.NoticePgOperators {
+				goto PgOperator
+			}
 			token(Punctuation)
 		case '@':
 			if config.NoticeAtWord {
 				goto AtWordStart
 			} else if config.NoticeIdentifiers {
 				goto Identifier
+			} else if config.NoticePgOperators {
+				goto PgOperator
 			} else {
 				token(Punctuation)
 			}
 		case ';':
 			token(Semicolon)
+			if config.NoticeCopyData && isCopyFromStdin(tokens) {
+				copyPending = true
+			}
 		case '?':
+			if config.NoticeQuestionMark && config.NoticePgOperators && i < len(s) &&
+				(s[i] == '|' || s[i] == '&' || s[i] == '-' || s[i] == '#') {
+				// data ?| array['a']
+				goto PgOperator
+			}
 			if config.NoticeQuestionMark {
-				token(QuestionMark)
+				if config.EscapedQuestionMark && i < len(s) && s[i] == '?' {
+					i++
+					token(Punctuation)
+				} else {
+					token(QuestionMark)
+				}
+			} else if config.NoticePgOperators {
+				goto PgOperator
 			} else {
 				token(Punctuation)
 			}
 		case ' ', '\n', '\r', '\t', '\b', '\v', '\f':
+			if c == '\n' && copyPending {
+				token(Whitespace)
+				goto CopyData
+			}
 			goto Whitespace
 		case '.':
 			goto PossibleNumber
 		case ':':
-			if config.NoticeColonWord {
+			if config.NoticeColonWord && (bracketDepth == 0 || !config.ColonWordNotInBrackets) {
 				goto ColonWordStart
 			}
 			token(Punctuation)
-		case '~', '`', '!', '%', '^', '&', '*', '(', ')', '+', '=', '{', '}', '[', ']',
-			'|', '\\', '<', '>', ',':
+		case '`':
+			if config.NoticeBacktickIdentifiers {
+				goto BacktickIdentifier
+			}
+			if config.NoticePgOperators {
+				goto PgOperator
+			}
+			token(Punctuation)
+		case '&':
+			if config.NoticeAmpersandSubstitution {
+				if i < len(s) && isWordByte(s[i]) {
+					goto SubstitutionVariable
+				}
+				if i+1 < len(s) && s[i] == '&' && isWordByte(s[i+1]) {
+					i++
+					goto SubstitutionVariable
+				}
+			}
+			if config.NoticePgOperators {
+				goto PgOperator
+			}
+			token(Punctuation)
+		case '~', '!', '%', '^' /*&*/, '*', '+', '=', '|', '<', '>':
+			if config.NoticePgOperators {
+				goto PgOperator
+			}
+			token(Punctuation)
+		case '\\':
+			if config.NoticeBackslashG && i < len(s) && s[i] == 'G' {
+				i++
+				token(Semicolon)
+			} else {
+				token(Punctuation)
+			}
+		case '{':
+			if config.NoticeCurlyComments {
+				goto CurlyComment
+			}
+			token(Punctuation)
+		case '[':
+			if config.NoticeBracketIdentifiers {
+				goto BracketIdentifier
+			}
+			bracketDepth++
+			token(Punctuation)
+		case ']':
+			if bracketDepth > 0 {
+				bracketDepth--
+			}
+			token(Punctuation)
+		case '(', ')', '}', ',':
 			token(Punctuation)
 		case '$':
 			// $1
@@ -258,6 +699,9 @@ BaseState:
 			if config.NoticeDollarQuotes || config.NoticeDollarNumber {
 				goto Dollar
 			}
+			if config.NoticeMoneyConstants {
+				goto Money
+			}
 			token(Punctuation)
 		case 'U':
 			// U&'d\0061t\+000061'
@@ -299,9 +743,17 @@ BaseState:
 				goto DeliminatedString
 			}
 			goto Word
-		case 'a' /*b*/, 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm',
+		case 'e', 'E':
+			// E'a\'b'
+			if config.StandardConformingStrings && i < len(s) && s[i] == '\'' {
+				i++
+				escapeString = true
+				goto SingleQuoteString
+			}
+			goto Word
+		case 'a' /*b*/, 'c', 'd' /*e*/, 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm',
 			/*n*/ 'o', 'p' /*q*/, 'r', 's', 't', 'u', 'v', 'w' /*x*/, 'y', 'z',
-			'A' /*B*/, 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M',
+			'A' /*B*/, 'C', 'D' /*E*/, 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M',
 			/*N*/ 'O', 'P' /*Q*/, 'R', 'S', 'T' /*U*/, 'V', 'W' /*X*/, 'Y', 'Z',
 			'_':
 			// This covers the entire alphabet except specific letters that have
@@ -331,15 +783,30 @@ BaseState:
 			case unicode.IsDigit(r):
 				i += w - 1
 				goto Number
+			case config.NoticeMoneyConstants && strings.ContainsRune(config.CurrencyPrefixes, r):
+				// £10
+				i += w - 1
+				goto Money
 			case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsMark(r):
 				i += w - 1
 				token(Punctuation)
 			case unicode.IsLetter(r):
 				i += w - 1
+				if config.ASCIIOnlyIdentifiers {
+					token(Other)
+					continue
+				}
 				goto Word
-			case unicode.IsControl(r) || unicode.IsSpace(r):
+			case unicode.IsSpace(r):
 				i += w - 1
 				goto Whitespace
+			case unicode.IsControl(r):
+				i += w - 1
+				if config.ControlCharsAreOther {
+					token(Other)
+				} else {
+					goto Whitespace
+				}
 			default:
 				i += w - 1
 				token(Other)
@@ -353,15 +820,37 @@ CStyleComment:
 		c := s[i]
 		i++
 		switch c {
+		case '/':
+			if config.NestedComments && i < len(s) && s[i] == '*' {
+				i++
+				commentDepth++
+			}
 		case '*':
 			if i < len(s) && s[i] == '/' {
 				i++
-				token(Comment)
+				if commentDepth > 0 {
+					commentDepth--
+					continue
+				}
+				token(commentType)
+				commentType = Comment
 				goto BaseState
 			}
 		}
 	}
-	token(Comment)
+	unterminated(commentType)
+	goto Done
+
+CurlyComment:
+	for i < len(s) {
+		c := s[i]
+		i++
+		if c == '}' {
+			token(Comment)
+			goto BaseState
+		}
+	}
+	unterminated(Comment)
 	goto Done
 
 SingleQuoteString:
@@ -370,18 +859,27 @@ SingleQuoteString:
 		i++
 		switch c {
 		case '\'':
+			if escapeString && i < len(s) && s[i] == '\'' {
+				// E'a''b' embeds a quote; the rest is still escaped
+				i++
+				continue
+			}
 			token(Literal)
+			escapeString = false
 			goto BaseState
 		case '\\':
+			if (config.NoBackslashEscapes || config.StandardConformingStrings) && !escapeString {
+				continue
+			}
 			if i < len(s) {
 				i++
 			} else {
-				token(Literal)
+				unterminated(Literal)
 				goto Done
 			}
 		}
 	}
-	token(Literal)
+	unterminated(Literal)
 	goto Done
 
 DoubleQuoteString:
@@ -393,15 +891,66 @@ DoubleQuoteString:
 			token(Literal)
 			goto BaseState
 		case '\\':
+			if config.NoBackslashEscapes {
+				continue
+			}
 			if i < len(s) {
 				i++
 			} else {
-				token(Literal)
+				unterminated(Literal)
 				goto Done
 			}
 		}
 	}
-	token(Literal)
+	unterminated(Literal)
+	goto Done
+
+QuotedIdentifier:
+	for i < len(s) {
+		c := s[i]
+		i++
+		if c == '"' {
+			if i < len(s) && s[i] == '"' {
+				i++
+				continue
+			}
+			token(Identifier)
+			goto BaseState
+		}
+	}
+	unterminated(Identifier)
+	goto Done
+
+BracketIdentifier:
+	for i < len(s) {
+		c := s[i]
+		i++
+		if c == ']' {
+			if i < len(s) && s[i] == ']' {
+				i++
+				continue
+			}
+			token(Identifier)
+			goto BaseState
+		}
+	}
+	unterminated(Identifier)
+	goto Done
+
+BacktickIdentifier:
+	for i < len(s) {
+		c := s[i]
+		i++
+		if c == '`' {
+			if i < len(s) && s[i] == '`' {
+				i++
+				continue
+			}
+			token(Identifier)
+			goto BaseState
+		}
+	}
+	unterminated(Identifier)
 	goto Done
 
 SkipToEOL:
@@ -410,11 +959,18 @@ SkipToEOL:
 		i++
 		switch c {
 		case '\n':
-			token(Comment)
+			if config.BackslashLineContinuation && (s[i-2] == '\\' || (s[i-2] == '\r' && s[i-3] == '\\')) {
+				continue
+			}
+			token(commentType)
+			commentType = Comment
+			if copyPending {
+				goto CopyData
+			}
 			goto BaseState
 		}
 	}
-	token(Comment)
+	token(commentType)
 	goto Done
 
 Word:
@@ -437,6 +993,10 @@ Word:
 			if config.NoticeIdentifiers {
 				goto Identifier
 			}
+			if config.isIdentifierExtra(c) {
+				i++
+				continue
+			}
 			token(Word)
 			goto BaseState
 		case '\n', '\r', '\t', '\b', '\v', '\f', ' ',
@@ -444,6 +1004,10 @@ Word:
 			':', ';', '<', '=', '>', '?', /*@*/
 			'[', '\\', ']', '^' /*_*/, '`',
 			'{', '|', '}', '~':
+			if config.isIdentifierExtra(c) {
+				i++
+				continue
+			}
 			// minor optimization to avoid DecodeRuneInString
 			token(Word)
 			goto BaseState
@@ -462,7 +1026,7 @@ Word:
 			}
 		}
 		r, w := utf8.DecodeRuneInString(s[i:])
-		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		if (unicode.IsLetter(r) || unicode.IsDigit(r)) && !config.ASCIIOnlyIdentifiers {
 			i += w
 			continue
 		}
@@ -490,10 +1054,20 @@ ColonWordStart:
 			'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 			i++
 			goto ColonWord
+		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			if config.NoticeColonNumber {
+				// :1
+				for i < len(s) && isDigit(s[i]) {
+					i++
+				}
+				token(ColonNumber)
+				goto BaseState
+			}
+			token(Punctuation)
+			goto BaseState
 		case '\n', '\r', '\t', '\b', '\v', '\f', ' ',
 			'!', '"', '#', '$', '%', '&', '\'', '(', ')', '*', '+', '-', '.', '/',
 			/*:*/ ';', '<', '=', '>', '?', '@',
-			'0', '1', '2', '3', '4', '5', '6', '7', '8', '9',
 			'[', '\\', ']', '^', '_', '`',
 			'{', '|', '}', '~':
 			// minor optimization to avoid DecodeRuneInString
@@ -531,6 +1105,8 @@ ColonWord:
 				i++
 				continue
 			}
+			token(ColonWord)
+			goto BaseState
 		case '\n', '\r', '\t', '\b', '\v', '\f', ' ',
 			'!', '"', '#', '$', '%', '&', '\'', '(', ')', '*', '+', '-' /*.*/, '/',
 			':', ';', '<', '=', '>', '?', '@',
@@ -594,6 +1170,18 @@ AtWordStart:
 			'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 			i++
 			goto AtWord
+		case '@':
+			if config.NoticeSystemVariables && i+1 < len(s) && isLetterByte(s[i+1]) {
+				i += 2
+				goto SystemVariable
+			}
+			if config.NoticeIdentifiers {
+				goto Identifier
+			}
+			// @@
+			i++
+			token(Punctuation)
+			goto BaseState
 		default:
 			if config.NoticeIdentifiers {
 				goto Identifier
@@ -632,6 +1220,38 @@ AtWord:
 	token(AtWord)
 	goto Done
 
+SystemVariable:
+	for i < len(s) {
+		c := s[i]
+		switch c {
+		case 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm',
+			'n', 'o', 'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z',
+			'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M',
+			'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z',
+			'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '_':
+			i++
+			continue
+		case '#', '@', '$':
+			if config.NoticeIdentifiers {
+				goto Identifier
+			}
+			token(SystemVariable)
+			goto BaseState
+		default:
+			token(SystemVariable)
+			goto BaseState
+		}
+	}
+	token(SystemVariable)
+	goto Done
+
+SubstitutionVariable:
+	for i < len(s) && isWordByte(s[i]) {
+		i++
+	}
+	token(SubstitutionVariable)
+	goto BaseState
+
 PossibleNumber:
 	if i < len(s) {
 		c := s[i]
@@ -715,12 +1335,19 @@ Number:
 			'\n', '\r', '\t', '\b', '\v', '\f', ' ',
 			'!', '"', '#', '$', '%', '&', '\'', '(', ')', '*', '+', '-' /*.*/, '/',
 			':', ';', '<', '=', '>', '?', '@',
-			'[', '\\', ']', '^', '_', '`',
+			'[', '\\', ']', '^' /*_*/, '`',
 			'{', '|', '}', '~':
 			// minor optimization to avoid DecodeRuneInString
 			i--
 			token(Number)
 			goto BaseState
+		case '_':
+			if config.NoticeDigitSeparators && isDigitSeparator(s, i, isDigit) {
+				continue
+			}
+			i--
+			token(Number)
+			goto BaseState
 		default:
 			r, w := utf8.DecodeRuneInString(s[i-1:])
 			if r == '⎖' {
@@ -769,12 +1396,19 @@ NumberNoDot:
 			'\n', '\r', '\t', '\b', '\v', '\f', ' ',
 			'!', '"', '#', '$', '%', '&', '\'', '(', ')', '*', '+', '-', '.', '/',
 			':', ';', '<', '=', '>', '?', '@',
-			'[', '\\', ']', '^', '_', '`',
+			'[', '\\', ']', '^' /*_*/, '`',
 			'{', '|', '}', '~':
 			// minor optimization to avoid DecodeRuneInString
 			i--
 			token(Number)
 			goto BaseState
+		case '_':
+			if config.NoticeDigitSeparators && isDigitSeparator(s, i, isDigit) {
+				continue
+			}
+			i--
+			token(Number)
+			goto BaseState
 		default:
 			r, w := utf8.DecodeRuneInString(s[i-1:])
 			if !unicode.IsDigit(r) {
@@ -808,12 +1442,19 @@ Exponent:
 			'\n', '\r', '\t', '\b', '\v', '\f', ' ',
 			'!', '"', '#', '$', '%', '&', '\'', '(', ')', '*', '+', '-', '.', '/',
 			':', ';', '<', '=', '>', '?', '@',
-			'[', '\\', ']', '^', '_', '`',
+			'[', '\\', ']', '^' /*_*/, '`',
 			'{', '|', '}', '~':
 			// minor optimization to avoid DecodeRuneInString
 			i--
 			token(Number)
 			goto BaseState
+		case '_':
+			if config.NoticeDigitSeparators && isDigitSeparator(s, i, isDigit) {
+				goto ExponentConfirmed
+			}
+			i--
+			token(Number)
+			goto BaseState
 		default:
 			r, w := utf8.DecodeRuneInString(s[i-1:])
 			if !unicode.IsDigit(r) {
@@ -848,12 +1489,19 @@ ExponentConfirmed:
 			'\n', '\r', '\t', '\b', '\v', '\f', ' ',
 			'!', '"', '#', '$', '%', '&', '\'', '(', ')', '*', '+', '-', '.', '/',
 			':', ';', '<', '=', '>', '?', '@',
-			'[', '\\', ']', '^', '_', '`',
+			'[', '\\', ']', '^' /*_*/, '`',
 			'{', '|', '}', '~':
 			// minor optimization to avoid DecodeRuneInString
 			i--
 			token(Number)
 			goto BaseState
+		case '_':
+			if config.NoticeDigitSeparators && isDigitSeparator(s, i, isDigit) {
+				continue
+			}
+			i--
+			token(Number)
+			goto BaseState
 		default:
 			r, w := utf8.DecodeRuneInString(s[i-1:])
 			if !unicode.IsDigit(r) {
@@ -876,6 +1524,65 @@ HexNumber:
 			'a', 'b', 'c', 'd', 'e', 'f',
 			'A', 'B', 'C', 'D', 'E', 'F':
 			// okay
+		case '_':
+			if config.NoticeDigitSeparators && isDigitSeparator(s, i, isHexByte) {
+				continue
+			}
+			i--
+			token(Number)
+			goto BaseState
+		case '.':
+			if config.NoticeHexFloat && i < len(s) && isHexByte(s[i]) {
+				goto HexFraction
+			}
+			i--
+			token(Number)
+			goto BaseState
+		case 'p', 'P':
+			if config.NoticeHexFloat {
+				if end := binaryExponentEnd(s, i); end != -1 {
+					i = end
+				} else {
+					i--
+				}
+			} else {
+				i--
+			}
+			token(Number)
+			goto BaseState
+		default:
+			i--
+			token(Number)
+			goto BaseState
+		}
+	}
+	token(Number)
+	goto Done
+
+HexFraction:
+	for i < len(s) {
+		c := s[i]
+		i++
+		switch c {
+		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9',
+			'a', 'b', 'c', 'd', 'e', 'f',
+			'A', 'B', 'C', 'D', 'E', 'F':
+			// okay
+		case '_':
+			if config.NoticeDigitSeparators && isDigitSeparator(s, i, isHexByte) {
+				continue
+			}
+			i--
+			token(Number)
+			goto BaseState
+		case 'p', 'P':
+			if end := binaryExponentEnd(s, i); end != -1 {
+				i = end
+			} else {
+				i--
+			}
+			token(Number)
+			goto BaseState
 		default:
 			i--
 			token(Number)
@@ -892,6 +1599,13 @@ BinaryNumber:
 		switch c {
 		case '0', '1':
 			// okay
+		case '_':
+			if config.NoticeDigitSeparators && isDigitSeparator(s, i, isBinaryByte) {
+				continue
+			}
+			i--
+			token(Number)
+			goto BaseState
 		default:
 			i--
 			token(Number)
@@ -906,7 +1620,12 @@ Whitespace:
 		c := s[i]
 		i++
 		switch c {
-		case ' ', '\n', '\r', '\t', '\b', '\v', '\f':
+		case '\n':
+			if copyPending {
+				token(Whitespace)
+				goto CopyData
+			}
+		case ' ', '\r', '\t', '\b', '\v', '\f':
 			// whitespace!
 		case 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm',
 			'n', 'o', 'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z',
@@ -923,7 +1642,7 @@ Whitespace:
 			goto BaseState
 		default:
 			r, w := utf8.DecodeRuneInString(s[i-1:])
-			if !unicode.IsSpace(r) && !unicode.IsControl(r) {
+			if !unicode.IsSpace(r) && (!unicode.IsControl(r) || config.ControlCharsAreOther) {
 				i--
 				token(Whitespace)
 				goto BaseState
@@ -952,7 +1671,7 @@ QuotedHexNumber:
 			goto BaseState
 		}
 	}
-	token(Number)
+	unterminated(Number)
 	goto Done
 
 QuotedBinaryNumber:
@@ -971,7 +1690,7 @@ QuotedBinaryNumber:
 			goto BaseState
 		}
 	}
-	token(Number)
+	unterminated(Number)
 	goto Done
 
 DeliminatedString:
@@ -1040,19 +1759,20 @@ DeliminatedStringCharacter:
 			goto BaseState
 		}
 	}
-	token(Literal)
+	unterminated(Literal)
 	goto Done
 
 DeliminatedStringRune:
 	for i < len(s) {
 		r, w := utf8.DecodeRuneInString(s[i:])
 		i += w
-		if r == runeDelim {
+		if r == runeDelim && i < len(s) && s[i] == '\'' {
+			i++
 			token(Literal)
 			goto BaseState
 		}
 	}
-	token(Literal)
+	unterminated(Literal)
 	goto Done
 
 Dollar:
@@ -1065,6 +1785,11 @@ Dollar:
 		if config.NoticeDollarQuotes {
 			if c == '$' {
 				e := strings.Index(s[i+1:], "$$")
+				if e == -1 && config.UnterminatedDollarIsLiteral {
+					i = len(s)
+					unterminated(Literal)
+					goto Done
+				}
 				if e == -1 {
 					i = firstDollarEnd
 					// $
@@ -1075,8 +1800,12 @@ Dollar:
 				token(Literal)
 				goto BaseState
 			}
+			// Tags start with a letter or underscore and continue with
+			// letters, digits, and underscores.  The body ends at the
+			// first exact copy of the opening tag so differently named
+			// tags inside the body are just text.
 			r, w := utf8.DecodeRuneInString(s[i:])
-			if unicode.IsLetter(r) {
+			if unicode.IsLetter(r) || c == '_' {
 				i += w
 				for i < len(s) {
 					// nolint:govet
@@ -1086,6 +1815,11 @@ Dollar:
 					if c == '$' {
 						endToken := s[tokenStart:i]
 						e := strings.Index(s[i:], endToken)
+						if e == -1 && config.UnterminatedDollarIsLiteral {
+							i = len(s)
+							unterminated(Literal)
+							goto Done
+						}
 						if e == -1 {
 							i = firstDollarEnd
 							// $
@@ -1095,7 +1829,7 @@ Dollar:
 						i += e + len(endToken)
 						token(Literal)
 						goto BaseState
-					} else if unicode.IsLetter(r) {
+					} else if unicode.IsLetter(r) || isDigit(c) || c == '_' {
 						i += w - 1
 						continue
 					} else {
@@ -1133,30 +1867,312 @@ Dollar:
 	token(Punctuation)
 	goto Done
 
+Money:
+	// $10
+	// $10.32
+	// $.5
+	// $-10
+	// We arrive here with i just past the $ or other currency symbol
+	firstDollarEnd = i
+	if i+1 < len(s) && (s[i] == '-' || s[i] == '+') && (isDigit(s[i+1]) || s[i+1] == '.') {
+		i++
+	}
+	if i < len(s) && (isDigit(s[i]) || (s[i] == '.' && i+1 < len(s) && isDigit(s[i+1]))) {
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		if i < len(s) && s[i] == '.' {
+			i++
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+		}
+		token(Money)
+		goto BaseState
+	}
+	// $
+	i = firstDollarEnd
+	token(Punctuation)
+	goto BaseState
+
+TempTableName:
+	// #temp
+	// ##global
+	if i < len(s) && s[i] == '#' {
+		i++
+	}
+	if i < len(s) && isWordByte(s[i]) {
+		for i < len(s) && isWordByte(s[i]) {
+			i++
+		}
+		token(Identifier)
+		goto BaseState
+	}
+	// # ##
+	token(Punctuation)
+	goto BaseState
+
+PgOperator:
+	// We arrive here with tokenStart on the first operator character
+	i = tokenStart + pgOperatorLength(s[tokenStart:], config.DashDashRequiresSpace)
+	token(Operator)
+	goto BaseState
+
+CopyData:
+	// We arrive here at the start of the line after COPY ... FROM STDIN;
+	copyPending = false
+	for i < len(s) {
+		e := strings.IndexByte(s[i:], '\n')
+		if e == -1 {
+			e = len(s) - i
+		}
+		if strings.TrimSuffix(s[i:i+e], "\r") == `\.` {
+			i += 2
+			token(CopyData)
+			goto BaseState
+		}
+		i += e
+		if i < len(s) {
+			i++
+		}
+	}
+	unterminated(CopyData)
+	goto Done
+
 Done:
+	if config.MaxTokens > 0 && len(tokens) > config.MaxTokens {
+		tokens = truncateTokens(s, tokens, config.MaxTokens)
+	}
+	if config.NoticeTypedLiterals {
+		tokens = combineTypedLiterals(tokens)
+	}
 	return tokens
 }
 
+// truncateTokens keeps the first n tokens and replaces the rest,
+// along with any of s that was not tokenized, with a Truncated token
+func truncateTokens(s string, tokens Tokens, n int) Tokens {
+	var end int
+	for _, t := range tokens[:n] {
+		end += len(t.Text)
+	}
+	return append(tokens[:n], Token{
+		Type: Truncated,
+		Text: s[end:],
+	})
+}
+
+// runLimitCut returns how much of text, which is longer than limit,
+// goes in the first token: limit bytes unless that would split a
+// character.  A character is never split, even if it is longer than
+// limit, so the result may be all of text.
+func runLimitCut(text string, limit int) int {
+	n := limit
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	if n == 0 {
+		_, n = utf8.DecodeRuneInString(text)
+	}
+	return n
+}
+
+// punctuationCombineOkay reports whether merging Punctuation into a
+// token that would be n bytes long is allowed
+func (c Config) punctuationCombineOkay(n int) bool {
+	return !c.SeparatePunctuation && (c.PunctuationRunLimit <= 0 || n <= c.PunctuationRunLimit)
+}
+
+func (c Config) isIdentifierExtra(b byte) bool {
+	return c.IdentifierExtraChars != "" && strings.IndexByte(c.IdentifierExtraChars, b) != -1
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// combineTypedLiterals merges a DATE, TIME, TIMESTAMP, or INTERVAL
+// Word and the Literal that follows it into one TypedLiteral. It
+// re-uses the storage of ts.
+func combineTypedLiterals(ts Tokens) Tokens {
+	c := ts[:0]
+	for i := 0; i < len(ts); i++ {
+		if ts[i].Type == Word {
+			switch strings.ToUpper(ts[i].Text) {
+			case "DATE", "TIME", "TIMESTAMP", "INTERVAL":
+				j := i + 1
+				if j < len(ts) && ts[j].Type == Whitespace {
+					j++
+				}
+				if j < len(ts) && ts[j].Type == Literal {
+					c = append(c, Token{
+						Type:         TypedLiteral,
+						Text:         ts[i : j+1].String(),
+						Unterminated: ts[j].Unterminated,
+					})
+					i = j
+					continue
+				}
+			}
+		}
+		c = append(c, ts[i])
+	}
+	return c
+}
+
+// isCopyFromStdin returns true if the command that ends with the
+// Semicolon at the end of ts is COPY ... FROM STDIN
+func isCopyFromStdin(ts Tokens) bool {
+	var words []string
+	for i := len(ts) - 2; i >= 0 && ts[i].Type != Semicolon; i-- {
+		if ts[i].Type != Whitespace && ts[i].Type != Comment {
+			words = append(words, strings.ToUpper(ts[i].Text))
+		}
+	}
+	// words are in reverse order
+	if len(words) == 0 || words[len(words)-1] != "COPY" {
+		return false
+	}
+	for i := 1; i < len(words); i++ {
+		if words[i] == "FROM" && words[i-1] == "STDIN" {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpaceByte(c byte) bool {
+	switch c {
+	case ' ', '\n', '\r', '\t', '\v', '\f':
+		return true
+	}
+	return false
+}
+
+func isWordByte(c byte) bool {
+	return isDigit(c) || c == '_' || isLetterByte(c)
+}
+
+func isLetterByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isBinaryByte(c byte) bool {
+	return c == '0' || c == '1'
+}
+
+// isDigitSeparator reports whether the _ at s[i-1] is followed by a
+// digit and comes after a digit or the x or b of a 0x or 0b prefix
+func isDigitSeparator(s string, i int, digit func(byte) bool) bool {
+	if i < 2 || i >= len(s) || !digit(s[i]) {
+		return false
+	}
+	return digit(s[i-2]) || s[i-2] == 'x' || s[i-2] == 'b'
+}
+
+func isHexByte(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// binaryExponentEnd returns the end of the signed decimal exponent
+// that follows the p of a hex float, which is at s[i-1], or -1 if
+// there are no digits
+func binaryExponentEnd(s string, i int) int {
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	start := i
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	if i == start {
+		return -1
+	}
+	return i
+}
+
+// pgOperatorLength returns the length of the PostgreSQL operator at the
+// start of s. A run of operator characters is cut short by the start of
+// a comment and a multi-character operator may not end in + or - unless
+// it also contains one of ~ ! @ # % ^ & | ` ?  With dashDashRequiresSpace,
+// only a -- that is followed by whitespace or the end of s starts a
+// comment.
+// https://www.postgresql.org/docs/current/sql-syntax-lexical.html#SQL-SYNTAX-OPERATORS
+func pgOperatorLength(s string, dashDashRequiresSpace bool) int {
+	var special bool
+	n := 0
+	for n < len(s) {
+		switch s[n] {
+		case '-':
+			if n+1 < len(s) && s[n+1] == '-' &&
+				(!dashDashRequiresSpace || n+2 >= len(s) || isSpaceByte(s[n+2])) {
+				goto Trim
+			}
+		case '/':
+			if n+1 < len(s) && s[n+1] == '*' {
+				goto Trim
+			}
+		case '+', '*', '<', '>', '=':
+			// okay
+		case '~', '!', '@', '#', '%', '^', '&', '|', '`', '?':
+			special = true
+		default:
+			goto Trim
+		}
+		n++
+	}
+Trim:
+	if !special {
+		for n > 1 && (s[n-1] == '+' || s[n-1] == '-') {
+			n--
+		}
+	}
+	return n
+}
+
 func (ts Tokens) String() string {
 	if len(ts) == 0 {
 		return ""
 	}
-	strs := make([]string, len(ts))
-	for i, t := range ts {
-		strs[i] = t.Text
+	var b strings.Builder
+	b.Grow(ts.ByteLen())
+	for _, t := range ts {
+		b.WriteString(t.Text)
 	}
-	return strings.Join(strs, "")
+	return b.String()
 }
 
 // Strip removes leading/trailing whitespace and semicolors
-// and strips all internal comments.  Internal whitespace
-// is changed to a single space.
+// and strips all internal comments and byte order marks.
+// Internal whitespace is changed to a single space.  An internal
+// comment is treated as whitespace so that stripping it cannot join
+// the tokens on either side.
 func (ts Tokens) Strip() Tokens {
+	return ts.StripWith(StripOpts{})
+}
+
+// StripOpts adjust the behavior of StripWith
+type StripOpts struct {
+	// WhitespaceReplacement is used in place of each run of internal
+	// whitespace.  The default is " ".
+	WhitespaceReplacement string
+	// KeepTrailingDelimiter leaves a single delimiter at the end of
+	// the output if there were any after the last non-whitespace
+	// token.  The first one is kept, so SELECT 1\G; keeps \G.
+	KeepTrailingDelimiter bool
+}
+
+// StripWith is Strip with options
+func (ts Tokens) StripWith(opts StripOpts) Tokens {
+	ws := opts.WhitespaceReplacement
+	if ws == "" {
+		ws = " "
+	}
 	i := 0
 	for i < len(ts) {
 		// nolint:exhaustive
 		switch ts[i].Type {
-		case Comment, Whitespace, Semicolon:
+		case Comment, Whitespace, Semicolon, BOM:
 			i++
 			continue
 		}
@@ -1167,12 +2183,15 @@ func (ts Tokens) Strip() Tokens {
 	for ; i < len(ts); i++ {
 		// nolint:exhaustive
 		switch ts[i].Type {
-		case Comment:
+		case BOM:
 			continue
-		case Whitespace:
+		case Whitespace, Comment:
+			if c[len(c)-1].Type == Whitespace {
+				continue
+			}
 			c = append(c, Token{
 				Type: Whitespace,
-				Text: " ",
+				Text: ws,
 			})
 		case Semicolon:
 			c = append(c, ts[i])
@@ -1181,27 +2200,221 @@ func (ts Tokens) Strip() Tokens {
 			lastReal = len(c)
 		}
 	}
-	c = c[:lastReal]
-	return c
+	var delimiter *Token
+	if lastReal > 0 {
+		for i := lastReal; i < len(c); i++ {
+			if c[i].Type == Semicolon {
+				delimiter = &c[i]
+				break
+			}
+		}
+	}
+	if delimiter != nil && opts.KeepTrailingDelimiter {
+		d := *delimiter
+		// ;; is merged into one token, keep just the first
+		if strings.HasPrefix(d.Text, `\G`) {
+			d.Text = `\G`
+		} else {
+			d.Text = d.Text[:1]
+		}
+		return append(c[:lastReal], d)
+	}
+	return c[:lastReal]
+}
+
+// SplitOn breaks up the token array into multiple token arrays
+// wherever isBoundary returns true.  The boundary tokens are dropped
+// and the segments are not stripped.
+func (ts Tokens) SplitOn(isBoundary func(Token) bool) TokensList {
+	var r TokensList
+	start := 0
+	for i, t := range ts {
+		if isBoundary(t) {
+			r = append(r, ts[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(ts) {
+		r = append(r, ts[start:])
+	}
+	return r
 }
 
 // CmdSplit breaks up the token array into multiple token arrays,
 // one per command (splitting on ";")
 func (ts Tokens) CmdSplit() TokensList {
+	r := ts.SplitOn(func(t Token) bool {
+		return t.Type == Semicolon
+	})
+	for i, cmd := range r {
+		r[i] = cmd.Strip()
+	}
+	return r
+}
+
+// CmdSplitWithLeadingComments breaks up the token array into one
+// token array per command, like CmdSplit, but keeps the comments that
+// come before each command with that command.  A comment on the same
+// line as the previous command's ";" stays with the previous command.
+// Comments after the last command are kept with the last command.
+// Commands do not include their ";".  Whitespace is trimmed from the
+// ends of each command but is otherwise left alone.
+func (ts Tokens) CmdSplitWithLeadingComments() TokensList {
+	var r TokensList
+	var pending Tokens
+	for _, part := range ts.SplitOn(func(t Token) bool {
+		return t.Type == Semicolon
+	}) {
+		if len(r) > 0 {
+			var trailing Tokens
+			trailing, part = splitSameLine(part)
+			r[len(r)-1] = r[len(r)-1].Concat(trailing)
+		}
+		if part.isWhitespaceOnly() {
+			pending = pending.Concat(part)
+			continue
+		}
+		r = append(r, pending.Concat(part))
+		pending = nil
+	}
+	if len(r) > 0 {
+		r[len(r)-1] = r[len(r)-1].Concat(pending)
+	} else if len(pending) > 0 {
+		r = append(r, pending)
+	}
+	for i, cmd := range r {
+		for len(cmd) > 0 && cmd[0].Type == Whitespace {
+			cmd = cmd[1:]
+		}
+		for len(cmd) > 0 && cmd[len(cmd)-1].Type == Whitespace {
+			cmd = cmd[:len(cmd)-1]
+		}
+		r[i] = cmd
+	}
+	return r
+}
+
+// CmdSplitUnstrippedAttachLeading breaks up the token array into one
+// token array per command without stripping anything.  Each command
+// ends with its ";", so the whitespace and comments between two
+// commands are at the start of the following command.  Anything
+// after the last ";" that is only whitespace and comments is kept
+// with the last command.  Concatenating the commands gives back
+// ts.String() exactly.
+func (ts Tokens) CmdSplitUnstrippedAttachLeading() TokensList {
 	var r TokensList
 	start := 0
 	for i, t := range ts {
 		if t.Type == Semicolon {
-			r = append(r, Tokens(ts[start:i]).Strip())
+			r = append(r, ts[start:i+1])
 			start = i + 1
 		}
 	}
-	if start < len(ts) {
-		r = append(r, Tokens(ts[start:]).Strip())
+	rest := ts[start:]
+	switch {
+	case len(rest) == 0:
+	case len(r) > 0 && rest.isWhitespaceOnly():
+		r[len(r)-1] = r[len(r)-1].Concat(rest)
+	default:
+		r = append(r, rest)
 	}
 	return r
 }
 
+// splitSameLine splits off the whitespace and comments at the start
+// of ts that end on the first line.  A comment that starts on the
+// first line but continues onto the next is left in rest.  Since
+// Tokenize merges adjacent comments, a Comment token may be split.
+func splitSameLine(ts Tokens) (first Tokens, rest Tokens) {
+	for j, t := range ts {
+		switch t.Type {
+		case Whitespace:
+			if strings.Contains(t.Text, "\n") {
+				return ts[:j+1], ts[j+1:]
+			}
+			continue
+		case Comment:
+		default:
+			return ts[:j], ts[j:]
+		}
+		var n int
+		var ended bool
+		for _, comment := range splitComments(t.Text) {
+			nl := strings.IndexByte(comment, '\n')
+			if nl != -1 && nl != len(comment)-1 {
+				ended = true
+				break
+			}
+			n += len(comment)
+			if nl != -1 {
+				ended = true
+				break
+			}
+		}
+		if !ended {
+			continue
+		}
+		first = ts[:j:j]
+		if n > 0 {
+			first = append(first, Token{Type: Comment, Text: t.Text[:n]})
+		}
+		rest = ts[j+1:]
+		if n < len(t.Text) {
+			rest = append(Tokens{{Type: Comment, Text: t.Text[n:]}}, rest...)
+		}
+		return first, rest
+	}
+	return ts, nil
+}
+
+// ForEachCommand calls fn with each command in ts, in order, without
+// building a TokensList.  With stripped, fn gets the same commands as
+// CmdSplit; without, it gets the raw segments that SplitOn returns for
+// Semicolon boundaries.  It stops and returns the first error from fn.
+func (ts Tokens) ForEachCommand(stripped bool, fn func(Tokens) error) error {
+	start := 0
+	emit := func(cmd Tokens) error {
+		if stripped {
+			cmd = cmd.Strip()
+		}
+		return fn(cmd)
+	}
+	for i, t := range ts {
+		if t.Type == Semicolon {
+			if err := emit(ts[start:i]); err != nil {
+				return err
+			}
+			start = i + 1
+		}
+	}
+	if start < len(ts) {
+		return emit(ts[start:])
+	}
+	return nil
+}
+
+// StatementCount returns the number of non-empty statements in ts.
+// It is the same as len(ts.CmdSplit().Strings()) but does not
+// allocate.
+func (ts Tokens) StatementCount() int {
+	var n int
+	var inStatement bool
+	for _, t := range ts {
+		// nolint:exhaustive
+		switch t.Type {
+		case Semicolon:
+			inStatement = false
+		case Whitespace, Comment, BOM:
+		default:
+			if !inStatement && t.Text != "" {
+				n++
+				inStatement = true
+			}
+		}
+	}
+	return n
+}
+
 func (tl TokensList) Strings() []string {
 	r := make([]string, 0, len(tl))
 	for _, ts := range tl {
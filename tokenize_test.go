@@ -1,6 +1,9 @@
 package sqltoken
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -170,22 +173,22 @@ var commonCases = []Tokens{
 	{
 		{Type: Word, Text: "c18"},
 		{Type: Whitespace, Text: " "},
-		{Type: Literal, Text: "'unterminated "},
+		{Type: Literal, Text: "'unterminated ", Unterminated: true},
 	},
 	{
 		{Type: Word, Text: "c19"},
 		{Type: Whitespace, Text: " "},
-		{Type: Literal, Text: `"unterminated `},
+		{Type: Literal, Text: `"unterminated `, Unterminated: true},
 	},
 	{
 		{Type: Word, Text: "c20"},
 		{Type: Whitespace, Text: " "},
-		{Type: Literal, Text: `'unterminated \`},
+		{Type: Literal, Text: `'unterminated \`, Unterminated: true},
 	},
 	{
 		{Type: Word, Text: "c21"},
 		{Type: Whitespace, Text: " "},
-		{Type: Literal, Text: `"unterminated \`},
+		{Type: Literal, Text: `"unterminated \`, Unterminated: true},
 	},
 	{
 		{Type: Word, Text: "c22"},
@@ -227,7 +230,7 @@ var commonCases = []Tokens{
 	{
 		{Type: Word, Text: "c28"},
 		{Type: Whitespace, Text: " "},
-		{Type: Comment, Text: "/* foo "},
+		{Type: Comment, Text: "/* foo ", Unterminated: true},
 	},
 	{
 		{Type: Word, Text: "c29"},
@@ -385,6 +388,19 @@ var commonCases = []Tokens{
 		{Type: Whitespace, Text: " "},
 		{Type: Word, Text: "z"},
 	},
+	{
+		{Type: Word, Text: "c55"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "/*/ still a comment */"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "z"},
+	},
+	{
+		{Type: Word, Text: "c56"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "/**/"},
+		{Type: Word, Text: "z"},
+	},
 }
 
 var mySQLCases = []Tokens{
@@ -481,12 +497,12 @@ var mySQLCases = []Tokens{
 	{
 		{Type: Word, Text: "m12"},
 		{Type: Whitespace, Text: " "},
-		{Type: Number, Text: "b'10"},
+		{Type: Number, Text: "b'10", Unterminated: true},
 	},
 	{
 		{Type: Word, Text: "m13"},
 		{Type: Whitespace, Text: " "},
-		{Type: Number, Text: "x'1f"},
+		{Type: Number, Text: "x'1f", Unterminated: true},
 	},
 	{
 		{Type: Word, Text: "m14"},
@@ -581,14 +597,14 @@ var postgreSQLCases = []Tokens{
 	{
 		{Type: Word, Text: "p01"},
 		{Type: Whitespace, Text: " "},
-		{Type: Punctuation, Text: "#"},
+		{Type: Operator, Text: "#"},
 		{Type: Word, Text: "foo"},
 		{Type: Whitespace, Text: "\n"},
 	},
 	{
 		{Type: Word, Text: "p02"},
 		{Type: Whitespace, Text: " "},
-		{Type: Punctuation, Text: "?"},
+		{Type: Operator, Text: "?"},
 		{Type: Whitespace, Text: "\n"},
 	},
 	{
@@ -685,11 +701,13 @@ var postgreSQLCases = []Tokens{
 		{Type: Whitespace, Text: " "},
 		{Type: Punctuation, Text: "$"},
 		{Type: Word, Text: "foo"},
-		{Type: Punctuation, Text: "-$"},
+		{Type: Operator, Text: "-"},
+		{Type: Punctuation, Text: "$"},
 		{Type: Word, Text: "bar"},
 		{Type: Punctuation, Text: "$"},
 		{Type: Word, Text: "foo"},
-		{Type: Punctuation, Text: "-$"},
+		{Type: Operator, Text: "-"},
+		{Type: Punctuation, Text: "$"},
 		{Type: Whitespace, Text: " "},
 	},
 	{
@@ -707,7 +725,9 @@ var postgreSQLCases = []Tokens{
 	{
 		{Type: Word, Text: "p17"},
 		{Type: Whitespace, Text: " "},
-		{Type: Punctuation, Text: "=@:?"},
+		{Type: Operator, Text: "=@"},
+		{Type: Punctuation, Text: ":"},
+		{Type: Operator, Text: "?"},
 	},
 	{
 		{Type: Word, Text: "p18"},
@@ -761,7 +781,7 @@ var postgreSQLCases = []Tokens{
 	{
 		{Type: Word, Text: "p25"},
 		{Type: Whitespace, Text: " "},
-		{Type: Punctuation, Text: "@"},
+		{Type: Operator, Text: "@"},
 		{Type: Word, Text: "foo"},
 		{Type: Punctuation, Text: "$"},
 		{Type: Whitespace, Text: " "},
@@ -770,9 +790,9 @@ var postgreSQLCases = []Tokens{
 		{Type: Word, Text: "p26"},
 		{Type: Whitespace, Text: " "},
 		{Type: Word, Text: "f"},
-		{Type: Punctuation, Text: "#"},
+		{Type: Operator, Text: "#"},
 		{Type: Word, Text: "o"},
-		{Type: Punctuation, Text: "@"},
+		{Type: Operator, Text: "@"},
 		{Type: Word, Text: "o"},
 		{Type: Punctuation, Text: "$"},
 		{Type: Whitespace, Text: " "},
@@ -780,7 +800,7 @@ var postgreSQLCases = []Tokens{
 	{
 		{Type: Word, Text: "p27"},
 		{Type: Whitespace, Text: " "},
-		{Type: Punctuation, Text: "#"},
+		{Type: Operator, Text: "#"},
 		{Type: Word, Text: "foo"},
 		{Type: Whitespace, Text: " "},
 	},
@@ -795,14 +815,14 @@ var postgreSQLCases = []Tokens{
 		{Type: Word, Text: "p29"},
 		{Type: Whitespace, Text: " "},
 		{Type: Word, Text: "foo"},
-		{Type: Punctuation, Text: "@"},
+		{Type: Operator, Text: "@"},
 		{Type: Whitespace, Text: " "},
 	},
 	{
 		{Type: Word, Text: "p30"},
 		{Type: Whitespace, Text: " "},
 		{Type: Word, Text: "foo"},
-		{Type: Punctuation, Text: "#"},
+		{Type: Operator, Text: "#"},
 		{Type: Whitespace, Text: " "},
 	},
 	{
@@ -811,6 +831,174 @@ var postgreSQLCases = []Tokens{
 		{Type: Word, Text: "_foo"},
 		{Type: Whitespace, Text: " "},
 	},
+	{
+		{Type: Word, Text: "p32"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "<<>>"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "b"},
+	},
+	{
+		{Type: Word, Text: "p33"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Operator, Text: "@@"},
+		{Type: Word, Text: "b"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "||"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "c"},
+		{Type: Operator, Text: "=>"},
+		{Type: Number, Text: "1"},
+	},
+	{
+		{Type: Word, Text: "p34"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Operator, Text: "="},
+		{Type: Operator, Text: "-"},
+		{Type: Number, Text: "1"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "b"},
+		{Type: Operator, Text: "*"},
+		{Type: Operator, Text: "+"},
+		{Type: Operator, Text: "-"},
+		{Type: Word, Text: "c"},
+	},
+	{
+		{Type: Word, Text: "p35"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Operator, Text: "@-"},
+		{Type: Word, Text: "b"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "~+"},
+		{Type: Word, Text: "c"},
+	},
+	{
+		{Type: Word, Text: "p36"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Operator, Text: "<"},
+		{Type: Comment, Text: "-- c\n"},
+		{Type: Word, Text: "b"},
+		{Type: Operator, Text: ">"},
+		{Type: Comment, Text: "/* c */"},
+	},
+	{
+		{Type: Word, Text: "p37"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "count"},
+		{Type: Punctuation, Text: "("},
+		{Type: Operator, Text: "*"},
+		{Type: Punctuation, Text: "),"},
+		{Type: Word, Text: "x"},
+		{Type: Punctuation, Text: "::"},
+		{Type: Word, Text: "int"},
+	},
+	{
+		{Type: Word, Text: "p38"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$outer$ a $inner$ b $inner$ c $outer$"},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "p39"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$a$ $ab$ $b$ $$ $a$"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$$ $inner$ $$"},
+	},
+	{
+		{Type: Word, Text: "p40"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$fn_1$ BEGIN RETURN $x$a$x$; END $fn_1$"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$_$x$_$"},
+	},
+	{
+		{Type: Word, Text: "p41"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "$"},
+		{Type: Word, Text: "outer"},
+		{Type: Punctuation, Text: "$"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$inner$ x $inner$"},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "p42"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "data"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "?"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'key'"},
+	},
+	{
+		{Type: Word, Text: "p43"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "data"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "?|"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "array"},
+		{Type: Punctuation, Text: "["},
+		{Type: Literal, Text: "'a'"},
+		{Type: Punctuation, Text: ","},
+		{Type: Literal, Text: "'b'"},
+		{Type: Punctuation, Text: "]"},
+	},
+	{
+		{Type: Word, Text: "p44"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "data"},
+		{Type: Operator, Text: "?&"},
+		{Type: Word, Text: "keys"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Operator, Text: "?"},
+		{Type: Word, Text: "b"},
+	},
+	{
+		{Type: Word, Text: "p45"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `'a\'`},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `E'a\''`},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `e'\\'`},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `E''`},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `E'a''\'x'`},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `'b''\'`},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "E"},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "e1"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `E'unterminated\'`, Unterminated: true},
+	},
+	{
+		{Type: Word, Text: "p46"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "/* a /* b */ c *//**/"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "1"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "/* a /* b */", Unterminated: true},
+	},
 }
 
 var oracleCases = []Tokens{
@@ -915,8 +1103,7 @@ var oracleCases = []Tokens{
 	{
 		{Type: Word, Text: "o17"},
 		{Type: Whitespace, Text: " "},
-		{Type: Punctuation, Text: ":"},
-		{Type: Number, Text: "3"},
+		{Type: ColonNumber, Text: ":3"},
 		{Type: Whitespace, Text: " "},
 	},
 	{
@@ -937,6 +1124,13 @@ var oracleCases = []Tokens{
 		{Type: Whitespace, Text: " "},
 		{Type: ColonWord, Text: ":f"},
 	},
+	{
+		{Type: Word, Text: "o33"},
+		{Type: Whitespace, Text: " "},
+		{Type: ColonWord, Text: ":f"},
+		{Type: Punctuation, Text: "."},
+		{Type: Word, Text: "g"},
+	},
 	{
 		{Type: Word, Text: "o18"},
 		{Type: Whitespace, Text: " "},
@@ -958,6 +1152,121 @@ var oracleCases = []Tokens{
 		{Type: Word, Text: "foo"},
 		{Type: Whitespace, Text: " "},
 	},
+	{
+		{Type: Word, Text: "o21"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "SELECT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Hint, Text: "/*+ FULL(t) */"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "/* plain */"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "x"},
+	},
+	{
+		{Type: Word, Text: "o22"},
+		{Type: Whitespace, Text: " "},
+		{Type: Hint, Text: "--+ hint\n"},
+		{Type: Comment, Text: "-- plain\n"},
+		{Type: Word, Text: "x"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "--\n"},
+		{Type: Hint, Text: "--+"},
+	},
+	{
+		{Type: Word, Text: "o23"},
+		{Type: Whitespace, Text: " "},
+		{Type: Hint, Text: "/*+ INDEX(t idx) ", Unterminated: true},
+	},
+	{
+		{Type: Word, Text: "o24"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "/* +not a hint */"},
+		{Type: Punctuation, Text: "/"},
+		{Type: Comment, Text: "/*/+*/"},
+	},
+	{
+		{Type: Word, Text: "o25"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "q'!line1\nline2!'"},
+		{Type: Whitespace, Text: "\n"},
+		{Type: Literal, Text: "Q'[a\r\n]'"},
+	},
+	{
+		{Type: Word, Text: "o26"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "q'€it's€'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "q'€a€ b €'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "nq'ǝxǝ'"},
+	},
+	{
+		{Type: Word, Text: "o27"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "q'!one! two\n", Unterminated: true},
+	},
+	{
+		{Type: Word, Text: "o28"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "q'€a€", Unterminated: true},
+	},
+	{
+		{Type: Word, Text: "o29"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "q'<a>", Unterminated: true},
+	},
+	{
+		{Type: Word, Text: "o30"},
+		{Type: Whitespace, Text: " "},
+		{Type: ColonNumber, Text: ":1"},
+		{Type: Punctuation, Text: ","},
+		{Type: ColonNumber, Text: ":10"},
+		{Type: Punctuation, Text: ","},
+		{Type: ColonWord, Text: ":name"},
+		{Type: Punctuation, Text: ","},
+		{Type: Word, Text: "x"},
+		{Type: Punctuation, Text: "::"},
+		{Type: Word, Text: "int"},
+		{Type: Punctuation, Text: ","},
+		{Type: ColonNumber, Text: ":2"},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: ","},
+		{Type: Word, Text: "t"},
+		{Type: Punctuation, Text: "::"},
+		{Type: Number, Text: "1"},
+	},
+	{
+		{Type: Word, Text: "o31"},
+		{Type: Whitespace, Text: " "},
+		{Type: SubstitutionVariable, Text: "&v"},
+		{Type: Punctuation, Text: ","},
+		{Type: SubstitutionVariable, Text: "&&v_2"},
+		{Type: Punctuation, Text: ","},
+		{Type: SubstitutionVariable, Text: "&1"},
+		{Type: SubstitutionVariable, Text: "&b"},
+		{Type: Punctuation, Text: "."},
+		{Type: Word, Text: "x"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "&"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "b"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "&&&"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'&v'"},
+	},
+	{
+		{Type: Word, Text: "o32"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: SubstitutionVariable, Text: "&b"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: SubstitutionVariable, Text: "&&b"},
+	},
 }
 
 var sqlServerCases = []Tokens{
@@ -1071,77 +1380,1295 @@ var sqlServerCases = []Tokens{
 		{Type: Whitespace, Text: " "},
 		{Type: Punctuation, Text: "@"},
 	},
-}
-
-// SQLServer w/o AtWord
-var oddball1Cases = []Tokens{
 	{
-		{Type: Word, Text: "od1"},
+		{Type: Word, Text: "s20"},
 		{Type: Whitespace, Text: " "},
-		{Type: Identifier, Text: "@foo$"},
+		{Type: Money, Text: "$10"},
 		{Type: Whitespace, Text: " "},
+		{Type: Money, Text: "$10.32"},
+		{Type: Punctuation, Text: ","},
+		{Type: Money, Text: "$.5"},
 	},
 	{
-		{Type: Word, Text: "od2"},
+		{Type: Word, Text: "s21"},
 		{Type: Whitespace, Text: " "},
-		{Type: Identifier, Text: "@foo"},
+		{Type: Punctuation, Text: "$"},
+		{Type: Word, Text: "foo"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "a$b"},
 		{Type: Whitespace, Text: " "},
 	},
 	{
-		{Type: Word, Text: "od3"},
-		{Type: Whitespace, Text: " "},
-		{Type: Punctuation, Text: "@"},
+		{Type: Word, Text: "s22"},
 		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "$"},
 	},
 	{
-		{Type: Word, Text: "od4"},
+		{Type: Word, Text: "s23"},
 		{Type: Whitespace, Text: " "},
-		{Type: Identifier, Text: "@8"},
+		{Type: Punctuation, Text: "$."},
 		{Type: Whitespace, Text: " "},
+		{Type: Money, Text: "$1"},
+		{Type: Money, Text: "$2"},
 	},
 	{
-		{Type: Word, Text: "od5"},
+		{Type: Word, Text: "s24"},
 		{Type: Whitespace, Text: " "},
-		{Type: Identifier, Text: "@88"},
+		{Type: Word, Text: "SELECT"},
 		{Type: Whitespace, Text: " "},
-	},
-	{
-		{Type: Word, Text: "od6"},
+		{Type: Identifier, Text: "[N]"},
 		{Type: Whitespace, Text: " "},
-		{Type: Identifier, Text: "@88"},
+		{Type: Word, Text: "FROM"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "t"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "WHERE"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "c"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "="},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "N'x'"},
 	},
 	{
-		{Type: Word, Text: "od7"},
+		{Type: Word, Text: "s25"},
 		{Type: Whitespace, Text: " "},
-		{Type: Identifier, Text: "@8"},
+		{Type: Identifier, Text: "[n'x']"},
+		{Type: Punctuation, Text: "."},
+		{Type: Identifier, Text: "[my table]"},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: "[a]]b]"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "]"},
 	},
 	{
-		{Type: Word, Text: "od8"},
+		{Type: Word, Text: "s26"},
 		{Type: Whitespace, Text: " "},
-		{Type: Punctuation, Text: "@"},
+		{Type: Identifier, Text: "[/* -- ;']"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "[unterminated", Unterminated: true},
 	},
-}
-
-// SQLx
-var oddball2Cases = []Tokens{
 	{
-		{Type: Word, Text: "sqlx1"},
+		{Type: Word, Text: "s27"},
 		{Type: Whitespace, Text: " "},
-		{Type: Word, Text: "INSERT"},
+		{Type: SystemVariable, Text: "@@ROWCOUNT"},
+		{Type: Punctuation, Text: ","},
 		{Type: Whitespace, Text: " "},
-		{Type: Word, Text: "INTO"},
+		{Type: SystemVariable, Text: "@@max_connections"},
+		{Type: Punctuation, Text: ","},
 		{Type: Whitespace, Text: " "},
-		{Type: Word, Text: "foo"},
+		{Type: AtWord, Text: "@local"},
+		{Type: Punctuation, Text: ","},
 		{Type: Whitespace, Text: " "},
-		{Type: Punctuation, Text: "("},
-		{Type: Word, Text: "a"},
+		{Type: Identifier, Text: "@@b#ar"},
 		{Type: Punctuation, Text: ","},
-		{Type: Word, Text: "b"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "@@1"},
+		{Type: Punctuation, Text: "+"},
+		{Type: SystemVariable, Text: "@@IDENTITY"},
+	},
+	{
+		{Type: Word, Text: "s28"},
+		{Type: Whitespace, Text: " "},
+		{Type: Money, Text: "$-10"},
 		{Type: Punctuation, Text: ","},
-		{Type: Word, Text: "c"},
+		{Type: Money, Text: "$+10.5"},
 		{Type: Punctuation, Text: ","},
-		{Type: Word, Text: "d"},
-		{Type: Punctuation, Text: ")"},
+		{Type: Money, Text: "$-.5"},
+		{Type: Punctuation, Text: ",-"},
+		{Type: Money, Text: "$10"},
+		{Type: Punctuation, Text: ",$-"},
+		{Type: Word, Text: "x"},
+		{Type: Punctuation, Text: ",$-."},
+	},
+}
+
+var cockroachCases = []Tokens{
+	{
+		{Type: Word, Text: "cr01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "x"},
+		{Type: Punctuation, Text: "::"},
+		{Type: Word, Text: "INT8"},
+		{Type: Punctuation, Text: ","},
+		{Type: DollarNumber, Text: "$1"},
+		{Type: Punctuation, Text: "::"},
+		{Type: Word, Text: "STRING"},
+	},
+	{
+		{Type: Word, Text: "cr02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "x'1f'"},
+		{Type: Punctuation, Text: ","},
+		{Type: Word, Text: "U"},
+		{Type: Operator, Text: "&"},
+		{Type: Literal, Text: "'a'"},
+	},
+	{
+		{Type: Word, Text: "cr03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$$ SELECT 1; $$"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "j"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "->>"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'k'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "@>"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "b"},
+	},
+	{
+		{Type: Word, Text: "cr04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "/* a /* b */ c */"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "1"},
+	},
+}
+
+var hanaCases = []Tokens{
+	{
+		{Type: Word, Text: "h01"},
+		{Type: Whitespace, Text: " "},
+		{Type: ColonWord, Text: ":schema"},
+		{Type: Punctuation, Text: "."},
+		{Type: Identifier, Text: `"Table"`},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "h02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'it''s'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `'back\'`},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "h03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: `"say ""hi"""`},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: `"\"`},
+	},
+	{
+		{Type: Word, Text: "h04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: `"unterminated `, Unterminated: true},
+	},
+	{
+		{Type: Word, Text: "h05"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- c\n/* c */"},
+		{Type: Whitespace, Text: " "},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Punctuation, Text: ","},
+		{Type: ColonWord, Text: ":name"},
+	},
+	{
+		{Type: Word, Text: "h06"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "x'1f'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "X'AF'"},
+	},
+}
+
+var bigQueryCases = []Tokens{
+	{
+		{Type: Word, Text: "bq01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "`project.dataset.table`"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "`my-project.d.t`"},
+	},
+	{
+		{Type: Word, Text: "bq02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "`project`"},
+		{Type: Punctuation, Text: "."},
+		{Type: Identifier, Text: "`dataset`"},
+		{Type: Punctuation, Text: "."},
+		{Type: Identifier, Text: "`table`"},
+		{Type: Punctuation, Text: "."},
+		{Type: Word, Text: "col"},
+	},
+	{
+		{Type: Word, Text: "bq03"},
+		{Type: Whitespace, Text: " "},
+		{Type: AtWord, Text: "@param"},
+		{Type: Punctuation, Text: ","},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Punctuation, Text: ","},
+		{Type: Literal, Text: `"str"`},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "# comment"},
+	},
+}
+
+var h2Cases = []Tokens{
+	{
+		{Type: Word, Text: "hs01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "// comment\n"},
+		{Type: Word, Text: "x"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- c\n/* c */"},
+	},
+	{
+		{Type: Word, Text: "hs02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$$ return 1; $$"},
+		{Type: Semicolon, Text: ";"},
+	},
+	{
+		{Type: Word, Text: "hs03"},
+		{Type: Whitespace, Text: " "},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Punctuation, Text: ","},
+		{Type: ColonWord, Text: ":name"},
+		{Type: Punctuation, Text: ",$"},
+		{Type: Number, Text: "1"},
+	},
+	{
+		{Type: Word, Text: "hs04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `'it''s\'`},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: `"Table"`},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: "/"},
+		{Type: Word, Text: "b"},
+	},
+}
+
+var ansiCases = []Tokens{
+	{
+		{Type: Word, Text: "a01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'it''s'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `'back\'`},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "a02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: `"col"`},
+		{Type: Punctuation, Text: "."},
+		{Type: Identifier, Text: `"a ""quoted"" name"`},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "a03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `';\'`},
+		{Type: Semicolon, Text: ";"},
+		{Type: Identifier, Text: `"\"`},
+	},
+	{
+		{Type: Word, Text: "a04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "?"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "$"},
+		{Type: Number, Text: "1"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: ":"},
+		{Type: Word, Text: "x"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "@"},
+		{Type: Word, Text: "y"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "#"},
+		{Type: Word, Text: "z"},
+	},
+	{
+		{Type: Word, Text: "a05"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- c\n"},
+		{Type: Word, Text: "x"},
+		{Type: Comment, Text: "/* c */"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "0"},
+		{Type: Word, Text: "x1f"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "x"},
+		{Type: Literal, Text: "'1f'"},
+	},
+	{
+		{Type: Word, Text: "a06"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "n"},
+		{Type: Literal, Text: "'x'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "$$"},
+		{Type: Word, Text: "x"},
+		{Type: Punctuation, Text: "$$"},
+	},
+}
+
+// MySQL with \G
+var backslashGCases = []Tokens{
+	{
+		{Type: Word, Text: "g01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "SELECT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "1"},
+		{Type: Semicolon, Text: "\\G"},
+	},
+	{
+		{Type: Word, Text: "g02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "SELECT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "1"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "\\"},
+		{Type: Word, Text: "g"},
+	},
+	{
+		{Type: Word, Text: "g03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'\\G'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `"a\G"`},
+		{Type: Semicolon, Text: "\\G"},
+		{Type: Whitespace, Text: "\n"},
+		{Type: Word, Text: "SELECT"},
+	},
+	{
+		{Type: Word, Text: "g04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "\\"},
+	},
+}
+
+var informixCases = []Tokens{
+	{
+		{Type: Word, Text: "i01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "{ this is a comment }"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "SELECT"},
+	},
+	{
+		{Type: Word, Text: "i02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "{a\n'b */ -- c}"},
+		{Type: Literal, Text: "'}'"},
+		{Type: Punctuation, Text: "}"},
+	},
+	{
+		{Type: Word, Text: "i03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "{ {inner}"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "x"},
+		{Type: Punctuation, Text: "}"},
+	},
+	{
+		{Type: Word, Text: "i04"},
+		{Type: Whitespace, Text: " "},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- c\n/* c */{c}"},
+		{Type: Punctuation, Text: "("},
+		{Type: Literal, Text: `"{s}"`},
+		{Type: Punctuation, Text: ")"},
+	},
+	{
+		{Type: Word, Text: "i05"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "{ unterminated", Unterminated: true},
+	},
+}
+
+var doubleSlashCases = []Tokens{
+	{
+		{Type: Word, Text: "d01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "// c\n"},
+		{Type: Word, Text: "x"},
+	},
+	{
+		{Type: Word, Text: "d02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "// c"},
+	},
+	{
+		{Type: Word, Text: "d03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "/* block */"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "x"},
+	},
+	{
+		{Type: Word, Text: "d04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "/"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "b"},
+		{Type: Punctuation, Text: "/"},
+		{Type: Number, Text: "2"},
+	},
+	{
+		{Type: Word, Text: "d05"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'//'"},
+		{Type: Punctuation, Text: "-"},
+		{Type: Comment, Text: "///* x */\n"},
+	},
+}
+
+var typedLiteralCases = []Tokens{
+	{
+		{Type: Word, Text: "y01"},
+		{Type: Whitespace, Text: " "},
+		{Type: TypedLiteral, Text: "DATE '2020-01-01'"},
+		{Type: Punctuation, Text: ","},
+		{Type: TypedLiteral, Text: "time'12:00'"},
+		{Type: Punctuation, Text: ","},
+		{Type: TypedLiteral, Text: "TIMESTAMP \n '2020-01-01 12:00'"},
+	},
+	{
+		{Type: Word, Text: "y02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "x"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "DATE"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "y"},
+	},
+	{
+		{Type: Word, Text: "y03"},
+		{Type: Whitespace, Text: " "},
+		{Type: TypedLiteral, Text: "INTERVAL '1'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "DAY"},
+	},
+	{
+		{Type: Word, Text: "y04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "DATE"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "/* c */"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'2020-01-01'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "dates"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'x'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "date"},
+	},
+	{
+		{Type: Word, Text: "y05"},
+		{Type: Whitespace, Text: " "},
+		{Type: TypedLiteral, Text: "DATE 'it''s'"},
+		{Type: TypedLiteral, Text: "DATE ''"},
+	},
+	{
+		{Type: Word, Text: "y06"},
+		{Type: Whitespace, Text: " "},
+		{Type: TypedLiteral, Text: "DATE 'x", Unterminated: true},
+	},
+}
+
+// PostgreSQL w/ DashDashRequiresSpace
+var dashDashCases = []Tokens{
+	{
+		{Type: Word, Text: "dd01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- c\n--\tc\n--"},
+	},
+	{
+		{Type: Word, Text: "dd02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "-"},
+		{Type: Operator, Text: "-"},
+		{Type: Word, Text: "c"},
+	},
+	{
+		{Type: Word, Text: "dd03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Operator, Text: "-"},
+		{Type: Operator, Text: "-"},
+		{Type: Word, Text: "b"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "-"},
+		{Type: Number, Text: "1"},
+	},
+	{
+		{Type: Word, Text: "dd04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "-"},
+		{Type: Operator, Text: "-"},
+		{Type: Operator, Text: "-"},
+		{Type: Word, Text: "x"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- x"},
+	},
+	{
+		{Type: Word, Text: "dd05"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "<"},
+		{Type: Operator, Text: "-"},
+		{Type: Operator, Text: "-"},
+		{Type: Word, Text: "c"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "@--"},
+		{Type: Word, Text: "c"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "<"},
+		{Type: Comment, Text: "-- c"},
+	},
+}
+
+// MySQL w/ ControlCharsAreOther
+var controlCharsCases = []Tokens{
+	{
+		{Type: Word, Text: "cc01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Other, Text: "\x00"},
+	},
+	{
+		{Type: Word, Text: "cc02"},
+		{Type: Other, Text: "\x01\x00"},
+		{Type: Whitespace, Text: " \t\n"},
+		{Type: Other, Text: "\x1f"},
+		{Type: Word, Text: "x"},
+	},
+	{
+		{Type: Word, Text: "cc03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Other, Text: "\x7f"},
+		{Type: Whitespace, Text: "\u00a0\v\f\b"},
+		{Type: Other, Text: "\u009b"},
+		{Type: Literal, Text: "'\x00'"},
+	},
+}
+
+// MySQL w/ ASCIIOnlyIdentifiers
+var asciiOnlyCases = []Tokens{
+	{
+		{Type: Word, Text: "ao01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "e"},
+		{Type: Other, Text: "èҾ"},
+		{Type: Whitespace, Text: " "},
+		{Type: Other, Text: "Ҿ"},
+		{Type: Word, Text: "e"},
+		{Type: Other, Text: "èҾ"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a_1"},
+		{Type: Other, Text: "é"},
+		{Type: Word, Text: "b"},
+	},
+	{
+		{Type: Word, Text: "ao02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'eè'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: `"eè"`},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "⁖"},
+	},
+}
+
+// PostgreSQL w/ UnterminatedDollarIsLiteral
+var unterminatedDollarCases = []Tokens{
+	{
+		{Type: Word, Text: "ud01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$$ BEGIN\n  RETURN 1;\n", Unterminated: true},
+	},
+	{
+		{Type: Word, Text: "ud02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$fn$ SELECT $$x$$; $f$", Unterminated: true},
+	},
+	{
+		{Type: Word, Text: "ud03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$$ closed $$"},
+		{Type: Whitespace, Text: " "},
+		{Type: DollarNumber, Text: "$1"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "$"},
+		{Type: Word, Text: "a"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "$$", Unterminated: true},
+	},
+}
+
+// MySQL w/ HashCommentRequiresLineStartOrSpace
+var hashSpaceCases = []Tokens{
+	{
+		{Type: Comment, Text: "# hs01\n"},
+		{Type: Word, Text: "a"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "#c\n#d"},
+	},
+	{
+		{Type: Word, Text: "hs02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: "#"},
+		{Type: Word, Text: "c"},
+		{Type: Punctuation, Text: ",#"},
+		{Type: Whitespace, Text: "\t"},
+		{Type: Comment, Text: "#x"},
+	},
+	{
+		{Type: Word, Text: "hs03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "/* c */"},
+		{Type: Punctuation, Text: "#"},
+		{Type: Literal, Text: "'s'"},
+		{Type: Punctuation, Text: "#"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- x\n# y"},
+	},
+}
+
+// PostgreSQL w/ QuestionMark
+var pgQuestionMarkCases = []Tokens{
+	{
+		{Type: Word, Text: "pq01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "WHERE"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "="},
+		{Type: Whitespace, Text: " "},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "AND"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "data"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "?|"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "array"},
+		{Type: Punctuation, Text: "["},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'b'"},
+		{Type: Punctuation, Text: "]"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "AND"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "data"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "?&"},
+		{Type: Whitespace, Text: " "},
+		{Type: QuestionMark, Text: "?"},
+	},
+	{
+		{Type: Word, Text: "pq02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "IN"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "("},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Punctuation, Text: ","},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Punctuation, Text: ")"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "OR"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "("},
+		{Type: Word, Text: "j"},
+		{Type: Punctuation, Text: ")"},
+		{Type: Whitespace, Text: " "},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'k'"},
+	},
+	{
+		{Type: Word, Text: "pq03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "WHERE"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "name"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "ILIKE"},
+		{Type: Whitespace, Text: " "},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "OR"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "p"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "?-|"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "q"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "OR"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "x"},
+		{Type: Whitespace, Text: " "},
+		{Type: Operator, Text: "?#"},
+		{Type: Whitespace, Text: " "},
+		{Type: QuestionMark, Text: "?"},
+	},
+}
+
+// SQLServer w/ CurrencyPrefixes "£€¥¤"
+var currencyCases = []Tokens{
+	{
+		{Type: Word, Text: "cp01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Money, Text: "£10"},
+		{Type: Punctuation, Text: ","},
+		{Type: Money, Text: "€10.50"},
+		{Type: Punctuation, Text: ","},
+		{Type: Money, Text: "¥-3"},
+		{Type: Punctuation, Text: ","},
+		{Type: Money, Text: "$1"},
+		{Type: Punctuation, Text: ","},
+		{Type: Money, Text: "¤.5"},
+	},
+	{
+		{Type: Word, Text: "cp02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "£"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "£"},
+		{Type: Word, Text: "x"},
+		{Type: Punctuation, Text: "£-."},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "₹"},
+		{Type: Number, Text: "10"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "£"},
+	},
+}
+
+// MySQL w/ BackslashLineContinuation
+var lineContinuationCases = []Tokens{
+	{
+		{Type: Word, Text: "lc01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- a\\\nb\n"},
+		{Type: Word, Text: "c"},
+	},
+	{
+		{Type: Word, Text: "lc02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- a\n"},
+		{Type: Word, Text: "b"},
+	},
+	{
+		{Type: Word, Text: "lc03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "# a\\\r\nb\\\n\\\nc \\ d\n"},
+		{Type: Literal, Text: "'\\\n'"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- e\\"},
+	},
+}
+
+// MySQL w/ HexFloat
+var hexFloatCases = []Tokens{
+	{
+		{Type: Word, Text: "hf01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "0x1.8p3"},
+		{Type: Punctuation, Text: ","},
+		{Type: Number, Text: "0xFF"},
+		{Type: Punctuation, Text: ","},
+		{Type: Number, Text: "0x1.8"},
+		{Type: Punctuation, Text: ","},
+		{Type: Number, Text: "0x1P-2"},
+		{Type: Punctuation, Text: ","},
+		{Type: Number, Text: "0xa.bp+10"},
+	},
+	{
+		{Type: Word, Text: "hf02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "0x1"},
+		{Type: Punctuation, Text: "."},
+		{Type: Word, Text: "x"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "0x1.8"},
+		{Type: Word, Text: "p"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "0x2"},
+		{Type: Word, Text: "p"},
+		{Type: Punctuation, Text: "-"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "0x3"},
+		{Type: Punctuation, Text: "."},
+	},
+}
+
+// PostgreSQL w/ HexNumbers, BinaryNumbers and DigitSeparators
+var digitSeparatorCases = []Tokens{
+	{
+		{Type: Word, Text: "ds01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "1_000"},
+		{Type: Punctuation, Text: ","},
+		{Type: Number, Text: "1_000_000.000_1"},
+		{Type: Punctuation, Text: ","},
+		{Type: Number, Text: "1e1_0"},
+		{Type: Punctuation, Text: ","},
+		{Type: Number, Text: "0x_FF"},
+		{Type: Punctuation, Text: ","},
+		{Type: Number, Text: "0xdead_beef"},
+		{Type: Punctuation, Text: ","},
+		{Type: Number, Text: "0b1_0"},
+	},
+	{
+		{Type: Word, Text: "ds02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "1"},
+		{Type: Word, Text: "__0"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "1"},
+		{Type: Word, Text: "_"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "_1"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "1."},
+		{Type: Word, Text: "_5"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "0x1"},
+		{Type: Word, Text: "_g"},
+	},
+}
+
+// MySQL w/ AnsiQuotes
+var mySQLAnsiQuotesCases = []Tokens{
+	{
+		{Type: Word, Text: "aq01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: `"col"`},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: `"a""b"`},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: `"a\"`},
+		{Type: Punctuation, Text: ","},
+		{Type: Literal, Text: `'x\'y'`},
+		{Type: Punctuation, Text: ",`"},
+		{Type: Word, Text: "t"},
+		{Type: Punctuation, Text: "`"},
+	},
+	{
+		{Type: Word, Text: "aq02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: `"unterminated`, Unterminated: true},
+	},
+}
+
+// MySQL w/ BacktickIdentifiers
+var backtickCases = []Tokens{
+	{
+		{Type: Word, Text: "bt01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "`old table`"},
+		{Type: Punctuation, Text: "."},
+		{Type: Identifier, Text: "`a``b`"},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: "`/* ; '`"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "`unterminated", Unterminated: true},
+	},
+	{
+		// backslash is not an escape in backtick identifiers
+		{Type: Word, Text: "bt02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "`a\\b`"},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: "`a\\`"},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: "`a``b`"},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: "`\\```"},
+	},
+}
+
+// Default config w/ ColonWord and AtWord
+var colonAtCases = []Tokens{
+	{
+		{Type: Word, Text: "ca01"},
+		{Type: Whitespace, Text: " "},
+		{Type: ColonWord, Text: ":a"},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: AtWord, Text: "@b"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "="},
+		{Type: Whitespace, Text: " "},
+		{Type: ColonWord, Text: ":c"},
+		{Type: Punctuation, Text: "::"},
+		{Type: Word, Text: "int"},
+	},
+	{
+		{Type: Word, Text: "ca02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "@@"},
+		{Type: Word, Text: "e"},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "@"},
+		{Type: Number, Text: "1"},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "@"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: ":"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "@"},
+	},
+}
+
+// PostgreSQL w/ CopyData
+var copyDataCases = []Tokens{
+	{
+		{Type: Word, Text: "cd01"},
+		{Type: Semicolon, Text: ";"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "COPY"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "t"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "("},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "b"},
+		{Type: Punctuation, Text: ")"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "FROM"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "stdin"},
+		{Type: Semicolon, Text: ";"},
+		{Type: Whitespace, Text: "\n"},
+		{Type: CopyData, Text: "1\tit's; -- not SQL\n2\t\\N\n\\."},
+		{Type: Whitespace, Text: "\n"},
+		{Type: Word, Text: "SELECT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'x'"},
+		{Type: Semicolon, Text: ";"},
+	},
+	{
+		{Type: Word, Text: "COPY"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "t"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "FROM"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "STDIN"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "WITH"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "CSV"},
+		{Type: Semicolon, Text: ";"},
+		{Type: Whitespace, Text: " "},
+		{Type: Comment, Text: "-- cd02\n"},
+		{Type: CopyData, Text: "a,\"b\\.\"\r\n\\."},
+		{Type: Whitespace, Text: "\r\n"},
+	},
+	{
+		{Type: Word, Text: "COPY"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "t"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "FROM"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "STDIN"},
+		{Type: Semicolon, Text: ";"},
+		{Type: Whitespace, Text: "\n"},
+		{Type: CopyData, Text: "cd03\n", Unterminated: true},
+	},
+	{
+		{Type: Word, Text: "COPY"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "t"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "TO"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "STDOUT"},
+		{Type: Semicolon, Text: ";"},
+		{Type: Whitespace, Text: "\n"},
+		{Type: Word, Text: "cd04"},
+		{Type: Whitespace, Text: "\n"},
+		{Type: Punctuation, Text: "\\."},
+	},
+}
+
+// MySQL w/ SeparatePunctuation
+var separatePunctuationCases = []Tokens{
+	{
+		{Type: Word, Text: "sp01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "="},
+		{Type: Punctuation, Text: "@"},
+		{Type: Punctuation, Text: ":"},
+		{Type: Punctuation, Text: "$"},
+	},
+	{
+		{Type: Word, Text: "sp02"},
+		{Type: Punctuation, Text: "("},
+		{Type: Punctuation, Text: "("},
+		{Type: Number, Text: "1"},
+		{Type: Punctuation, Text: ")"},
+		{Type: Punctuation, Text: ")"},
+		{Type: Semicolon, Text: ";;"},
+	},
+}
+
+// MySQL w/ PunctuationRunLimit 4
+var punctuationRunLimitCases = []Tokens{
+	{
+		{Type: Word, Text: "pl01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "=<>!"},
+		{Type: Punctuation, Text: "~^&|"},
+		{Type: Punctuation, Text: "*%"},
+	},
+	{
+		{Type: Word, Text: "pl02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "=@:$"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "("},
+		{Type: Number, Text: "1"},
+		{Type: Punctuation, Text: "))"},
+	},
+}
+
+// MySQL w/ EscapedQuestionMark
+var escapedQuestionMarkCases = []Tokens{
+	{
+		{Type: Word, Text: "q01"},
+		{Type: Whitespace, Text: " "},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Punctuation, Text: ","},
+		{Type: QuestionMark, Text: "?"},
+	},
+	{
+		{Type: Word, Text: "q02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "??"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'??'"},
+	},
+	{
+		{Type: Word, Text: "q03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "??"},
+		{Type: QuestionMark, Text: "?"},
+	},
+	{
+		{Type: Word, Text: "q04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "(??"},
+		{Type: Whitespace, Text: " "},
+		{Type: QuestionMark, Text: "?"},
+		{Type: Punctuation, Text: ")"},
+	},
+}
+
+// PostgreSQL w/ ColonWord and ColonWordNotInBrackets
+var colonBracketCases = []Tokens{
+	{
+		{Type: Word, Text: "b01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: "["},
+		{Type: Number, Text: "1"},
+		{Type: Punctuation, Text: ":"},
+		{Type: Number, Text: "3"},
+		{Type: Punctuation, Text: "]"},
+	},
+	{
+		{Type: Word, Text: "b02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: "[:"},
+		{Type: Word, Text: "n"},
+		{Type: Punctuation, Text: "]"},
+		{Type: Whitespace, Text: " "},
+		{Type: ColonWord, Text: ":n"},
+	},
+	{
+		{Type: Word, Text: "b03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: "["},
+		{Type: Word, Text: "b"},
+		{Type: Punctuation, Text: "["},
+		{Type: Number, Text: "1"},
+		{Type: Punctuation, Text: "]:"},
+		{Type: Word, Text: "x"},
+		{Type: Punctuation, Text: "]"},
+		{Type: ColonWord, Text: ":y"},
+	},
+	{
+		{Type: Word, Text: "b04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "]"},
+		{Type: ColonWord, Text: ":z"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'['"},
+		{Type: ColonWord, Text: ":z"},
+	},
+}
+
+// ANSI w/ IdentifierExtraChars "$#"
+var extraCharsCases = []Tokens{
+	{
+		{Type: Word, Text: "x01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "foo$bar"},
+		{Type: Punctuation, Text: ","},
+		{Type: Word, Text: "a#b$"},
+		{Type: Punctuation, Text: "."},
+		{Type: Word, Text: "c"},
+	},
+	{
+		{Type: Word, Text: "x02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "$"},
+		{Type: Word, Text: "x"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "#"},
+		{Type: Word, Text: "y"},
+	},
+	{
+		{Type: Word, Text: "x03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: "@"},
+		{Type: Word, Text: "b"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "ǝ$"},
+	},
+}
+
+// SQLServer w/o Identifiers, w/ TempTableNames
+var tempTableCases = []Tokens{
+	{
+		{Type: Word, Text: "t01"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "#t"},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: "#t_2"},
+		{Type: Punctuation, Text: "."},
+		{Type: Word, Text: "x"},
+	},
+	{
+		{Type: Word, Text: "t02"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "##g"},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "t03"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "#"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "##"},
+	},
+	{
+		{Type: Word, Text: "t04"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "##"},
+		{Type: Identifier, Text: "#x"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "#("},
+	},
+	{
+		{Type: Word, Text: "t05"},
+		{Type: Whitespace, Text: " "},
+		{Type: AtWord, Text: "@v"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "$"},
+		{Type: Word, Text: "a"},
+	},
+}
+
+// SQLServer w/o AtWord
+var oddball1Cases = []Tokens{
+	{
+		{Type: Word, Text: "od1"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "@foo$"},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "od2"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "@foo"},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "od3"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "@"},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "od4"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "@8"},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "od5"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "@88"},
+		{Type: Whitespace, Text: " "},
+	},
+	{
+		{Type: Word, Text: "od6"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "@88"},
+	},
+	{
+		{Type: Word, Text: "od7"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "@8"},
+	},
+	{
+		{Type: Word, Text: "od8"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "@"},
+	},
+}
+
+// SQLx
+var oddball2Cases = []Tokens{
+	{
+		{Type: Word, Text: "sqlx1"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "INSERT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "INTO"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "foo"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "("},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: ","},
+		{Type: Word, Text: "b"},
+		{Type: Punctuation, Text: ","},
+		{Type: Word, Text: "c"},
+		{Type: Punctuation, Text: ","},
+		{Type: Word, Text: "d"},
+		{Type: Punctuation, Text: ")"},
 		{Type: Whitespace, Text: " "},
 		{Type: Word, Text: "VALUES"},
 		{Type: Whitespace, Text: " "},
@@ -1175,7 +2702,7 @@ func doTests(t *testing.T, config Config, cases ...[]Tokens) {
 				t.Log("-----------------")
 				got := Tokenize(text, config)
 				require.Equal(t, text, got.String(), tc.String())
-				require.Equal(t, tc, got, tc.String())
+				require.Equal(t, tc, got, tc.String()+"\n"+tc.Diff(got))
 			})
 		}
 	}
@@ -1185,18 +2712,414 @@ func TestMySQLTokenizing(t *testing.T) {
 	doTests(t, MySQLConfig(), commonCases, mySQLCases)
 }
 
+func TestBacktickIdentifiers(t *testing.T) {
+	c := MySQLConfig()
+	c.NoticeBacktickIdentifiers = true
+	doTests(t, c, backtickCases)
+	require.Equal(t, Tokens{
+		{Type: Identifier, Text: "`my db`"},
+		{Type: Punctuation, Text: "."},
+		{Type: Identifier, Text: "`a``b`"},
+	}, Tokenize("`my db`.`a``b`", c))
+	require.Equal(t, Tokens{
+		{Type: Identifier, Text: "`p.d.t`"},
+	}, Tokenize("`p.d.t`", BigQueryConfig()))
+
+	require.Equal(t, Tokens{
+		{Type: Punctuation, Text: "`"},
+		{Type: Word, Text: "my"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "db"},
+		{Type: Punctuation, Text: "`"},
+	}, TokenizeMySQL("`my db`"), "only with the flag")
+	require.Equal(t, Tokens{
+		{Type: Punctuation, Text: "`"},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: "`"},
+	}, Tokenize("`a`", SQLServerConfig()), "other dialects")
+}
+
+// pgCommonExceptions are the commonCases that expect operator
+// characters to be generic Punctuation or \ to be an escape in '...'
+var pgCommonExceptions = []string{
+	"c04", "c05_singles", "c07_singles", "c08_doubles", "c09", "c10",
+	"c11", "c12", "c13", "c14", "c22", "c23", "c34",
+}
+
+// casesWithout returns the cases that do not start with one of names
+func casesWithout(cases []Tokens, names ...string) []Tokens {
+	skip := make(map[string]bool)
+	for _, name := range names {
+		skip[name] = true
+	}
+	var r []Tokens
+	for _, tc := range cases {
+		if len(tc) == 0 || !skip[tc[0].Text] {
+			r = append(r, tc)
+		}
+	}
+	return r
+}
+
 func TestPostgresSQLTokenizing(t *testing.T) {
-	doTests(t, PostgreSQLConfig(), commonCases, postgreSQLCases)
+	doTests(t, PostgreSQLConfig(), casesWithout(commonCases, pgCommonExceptions...), postgreSQLCases)
+
+	// the rest of commonCases, with the PostgreSQL features that
+	// they do not expect turned off
+	c := PostgreSQLConfig()
+	c.NoticePgOperators = false
+	c.StandardConformingStrings = false
+	doTests(t, c, commonCases)
+	require.Equal(t, "/* a /* b */", TokenizeMySQL("/* a /* b */ c */")[0].Text, "only with the flag")
+}
+
+func TestCockroachDBTokenizing(t *testing.T) {
+	doTests(t, CockroachDBConfig(), casesWithout(commonCases, pgCommonExceptions...), cockroachCases)
+
+	c := CockroachDBConfig()
+	c.NoticePgOperators = false
+	c.StandardConformingStrings = false
+	doTests(t, c, commonCases)
+}
+
+func TestPostgreSQLWithQuestionMarkTokenizing(t *testing.T) {
+	c := PostgreSQLConfig().WithNoticeQuestionMark()
+	doTests(t, c, pgQuestionMarkCases)
+	require.False(t, PostgreSQLConfig().NoticeQuestionMark, "copy")
+	ts := Tokenize("SELECT * FROM t WHERE a = ? AND data ?| array[?]", c)
+	require.Equal(t, []Param{
+		{Index: 1, Kind: QuestionMark, TokenIndex: 14},
+		{Index: 2, Kind: QuestionMark, TokenIndex: 24},
+	}, ts.Params())
+	ts = Tokenize("SELECT * FROM t WHERE name ILIKE ?", c)
+	require.Equal(t, []Param{
+		{Index: 1, Kind: QuestionMark, TokenIndex: 14},
+	}, ts.Params())
+}
+
+func TestPostgreSQLQuestionMarkIsNotParam(t *testing.T) {
+	ts := TokenizePostgreSQL("SELECT * FROM t WHERE data ? 'k' AND data ?| array['a'] AND data ?& $1")
+	require.Equal(t, []Param{{Index: 1, Kind: DollarNumber, TokenIndex: 33}}, ts.Params())
+
+	c := PostgreSQLConfig()
+	c.NoticePgOperators = false
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "data"},
+		{Type: Whitespace, Text: " "},
+		{Type: Punctuation, Text: "?|"},
+		{Type: Word, Text: "x"},
+	}, Tokenize("data ?|x", c))
 }
 
 func TestOracleTokenizing(t *testing.T) {
 	doTests(t, OracleConfig(), commonCases, oracleCases)
+	c := OracleConfig()
+	c.NoticeAmpersandSubstitution = false
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: "&"},
+		{Type: Word, Text: "b"},
+	}, Tokenize("a&b", c), "only with the flag")
 }
 
 func TestSQLServerTokenizing(t *testing.T) {
 	doTests(t, SQLServerConfig(), commonCases, sqlServerCases)
 }
 
+func TestHANATokenizing(t *testing.T) {
+	doTests(t, HANAConfig(), hanaCases)
+}
+
+func TestH2Tokenizing(t *testing.T) {
+	doTests(t, H2Config(), h2Cases)
+}
+
+func TestBigQueryTokenizing(t *testing.T) {
+	doTests(t, BigQueryConfig(), commonCases, bigQueryCases)
+}
+
+func TestANSITokenizing(t *testing.T) {
+	doTests(t, ANSIConfig(), ansiCases)
+}
+
+func TestInformixTokenizing(t *testing.T) {
+	doTests(t, InformixConfig(), commonCases, informixCases)
+	require.Equal(t, Tokens{
+		{Type: Punctuation, Text: "{"},
+		{Type: Word, Text: "x"},
+		{Type: Punctuation, Text: "}"},
+	}, Tokenize("{x}", MySQLConfig()), "only with NoticeCurlyComments")
+}
+
+func TestTempTableNamesTokenizing(t *testing.T) {
+	c := SQLServerConfig()
+	c.NoticeIdentifiers = false
+	c.NoticeTempTableNames = true
+	doTests(t, c, tempTableCases)
+
+	hash := MySQLConfig()
+	hash.NoticeTempTableNames = true
+	require.Equal(t, Tokens{
+		{Type: Comment, Text: "#t"},
+	}, Tokenize("#t", hash), "NoticeHashComment takes precedence")
+}
+
+func TestTypedLiteralsTokenizing(t *testing.T) {
+	c := MySQLConfig()
+	c.NoticeTypedLiterals = true
+	doTests(t, c, commonCases, typedLiteralCases)
+	require.Error(t, Tokenize("SELECT DATE 'x", c).ValidateBalanced())
+}
+
+func TestDashDashRequiresSpaceTokenizing(t *testing.T) {
+	c := PostgreSQLConfig()
+	c.DashDashRequiresSpace = true
+	doTests(t, c, dashDashCases)
+	require.Equal(t, Comment, TokenizePostgreSQL("--c")[0].Type, "only with the flag")
+
+	c = MySQLConfig()
+	c.DashDashRequiresSpace = true
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: "--"},
+		{Type: Word, Text: "b"},
+	}, Tokenize("a--b", c), "without NoticePgOperators")
+
+	c = OracleConfig()
+	c.DashDashRequiresSpace = true
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "SELECT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Hint, Text: "--+FULL(t)\n"},
+		{Type: Word, Text: "a"},
+	}, Tokenize("SELECT --+FULL(t)\na", c), "--+ is a hint")
+}
+
+func TestASCIIOnlyIdentifiersTokenizing(t *testing.T) {
+	c := MySQLConfig()
+	c.ASCIIOnlyIdentifiers = true
+	doTests(t, c, asciiOnlyCases)
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "eè"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "Ҿe"},
+	}, TokenizeMySQL("eè Ҿe"), "only with the flag")
+}
+
+func TestUnterminatedDollarIsLiteralTokenizing(t *testing.T) {
+	c := PostgreSQLConfig()
+	c.UnterminatedDollarIsLiteral = true
+	doTests(t, c, unterminatedDollarCases)
+	require.Equal(t, Tokens{
+		{Type: Punctuation, Text: "$"},
+		{Type: Word, Text: "fn"},
+		{Type: Punctuation, Text: "$"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "x"},
+	}, TokenizePostgreSQL("$fn$ x"), "only with the flag")
+}
+
+func TestHashCommentRequiresLineStartOrSpaceTokenizing(t *testing.T) {
+	c := MySQLConfig()
+	c.HashCommentRequiresLineStartOrSpace = true
+	doTests(t, c, hashSpaceCases)
+	require.Equal(t, Tokens{
+		{Type: Comment, Text: "#"},
+	}, Tokenize(utf8BOM+"#", c)[1:])
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "a"},
+		{Type: Comment, Text: "#c"},
+	}, TokenizeMySQL("a#c"), "only with the flag")
+}
+
+func TestCurrencyPrefixesTokenizing(t *testing.T) {
+	c := SQLServerConfig()
+	c.CurrencyPrefixes = "£€¥¤"
+	doTests(t, c, currencyCases)
+	require.Equal(t, Tokens{
+		{Type: Punctuation, Text: "£"},
+		{Type: Number, Text: "10"},
+	}, Tokenize("£10", SQLServerConfig()), "only $ by default")
+}
+
+func TestBackslashLineContinuationTokenizing(t *testing.T) {
+	c := MySQLConfig()
+	c.BackslashLineContinuation = true
+	doTests(t, c, lineContinuationCases)
+	require.Equal(t, Tokens{
+		{Type: Comment, Text: "-- a\\\n"},
+		{Type: Word, Text: "b"},
+	}, TokenizeMySQL("-- a\\\nb"), "only with the flag")
+}
+
+func TestHexFloatTokenizing(t *testing.T) {
+	c := MySQLConfig()
+	c.NoticeHexFloat = true
+	doTests(t, c, hexFloatCases)
+	require.Equal(t, Tokens{
+		{Type: Number, Text: "0x1"},
+		{Type: Number, Text: ".8"},
+		{Type: Word, Text: "p3"},
+	}, TokenizeMySQL("0x1.8p3"), "only with the flag")
+}
+
+func TestDigitSeparatorsTokenizing(t *testing.T) {
+	c := PostgreSQLConfig()
+	c.NoticeHexNumbers = true
+	c.NoticeBinaryNumbers = true
+	c.NoticeDigitSeparators = true
+	doTests(t, c, digitSeparatorCases)
+	require.Equal(t, Tokens{
+		{Type: Number, Text: "1"},
+		{Type: Word, Text: "_000"},
+	}, TokenizePostgreSQL("1_000"), "only with the flag")
+}
+
+func TestMySQLAnsiQuotesTokenizing(t *testing.T) {
+	c := MySQLConfig()
+	c.AnsiQuotes = true
+	doTests(t, c, mySQLAnsiQuotesCases)
+	require.Equal(t, Tokens{
+		{Type: Literal, Text: `"col"`},
+		{Type: Punctuation, Text: ","},
+		{Type: Literal, Text: `"a\"b"`},
+	}, TokenizeMySQL(`"col","a\"b"`), "only with the flag")
+}
+
+func TestColonAtTokenizing(t *testing.T) {
+	c := Config{
+		NoticeColonWord: true,
+		NoticeAtWord:    true,
+	}
+	doTests(t, c, colonAtCases)
+	require.Equal(t, []string{"a", "b"}, paramNames(Tokenize("SELECT :a, @b", c)))
+
+	c.NoticeIdentifiers = true
+	require.Equal(t, Tokens{
+		{Type: ColonWord, Text: ":a"},
+		{Type: Punctuation, Text: ","},
+		{Type: AtWord, Text: "@b"},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: "@b$c"},
+		{Type: Punctuation, Text: ","},
+		{Type: Identifier, Text: "@@e"},
+	}, Tokenize(":a,@b,@b$c,@@e", c), "NoticeIdentifiers")
+}
+
+func paramNames(ts Tokens) []string {
+	var names []string
+	for _, p := range ts.Params() {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestCopyDataTokenizing(t *testing.T) {
+	c := PostgreSQLConfig()
+	c.NoticeCopyData = true
+	doTests(t, c, copyDataCases)
+	require.Equal(t, []string{
+		"COPY t FROM STDIN",
+		"1\t2\n\\. SELECT 1",
+	}, Tokenize("COPY t FROM STDIN;\n1\t2\n\\.\nSELECT 1;", c).CmdSplit().Strings())
+	require.NotContains(t, TokenizePostgreSQL("COPY t FROM STDIN;\n1\n\\.\n"), Token{Type: CopyData, Text: "1\n\\."}, "only with the flag")
+
+	for _, input := range []string{"COPY t FROM STDIN;\n", "COPY t FROM STDIN; -- x\n"} {
+		ts := Tokenize(input, c)
+		require.Equal(t, input, ts.String())
+		require.NoError(t, ts.ValidateBalanced(), "COPY at the end of the input")
+		for _, tok := range ts {
+			require.False(t, tok.Unterminated, input)
+		}
+	}
+}
+
+func TestControlCharsAreOtherTokenizing(t *testing.T) {
+	c := MySQLConfig()
+	c.ControlCharsAreOther = true
+	doTests(t, c, controlCharsCases)
+
+	// without the flag, control characters are whitespace
+	for _, tc := range controlCharsCases {
+		for _, tok := range TokenizeMySQL(tc.String()) {
+			require.NotEqual(t, Other, tok.Type, tc.String())
+		}
+	}
+}
+
+func TestSeparatePunctuationTokenizing(t *testing.T) {
+	doTests(t, MySQLConfig().WithSeparatePunctuation(), separatePunctuationCases)
+	require.False(t, MySQLConfig().SeparatePunctuation)
+
+	c := MySQLConfig()
+	c.PunctuationRunLimit = 4
+	doTests(t, c, punctuationRunLimitCases)
+
+	c = OracleConfig()
+	c.PunctuationRunLimit = 4
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: "::::"},
+		{Type: Punctuation, Text: "::::"},
+		{Type: Punctuation, Text: ":"},
+		{Type: Word, Text: "b"},
+	}, Tokenize("a:::::::::b", c), "lexed as a unit")
+
+	c = MySQLConfig()
+	c.PunctuationRunLimit = 2
+	require.Equal(t, Tokens{
+		{Type: Punctuation, Text: "⁖"},
+		{Type: Punctuation, Text: "⁖"},
+		{Type: Punctuation, Text: "+"},
+	}, Tokenize("⁖⁖+", c), "characters are not split")
+	require.Equal(t, Tokens{
+		{Type: Punctuation, Text: "⁖"},
+	}, Tokenize("⁖", c), "a character longer than the limit")
+}
+
+func TestEscapedQuestionMarkTokenizing(t *testing.T) {
+	c := MySQLConfig()
+	c.EscapedQuestionMark = true
+	doTests(t, c, commonCases, escapedQuestionMarkCases)
+	require.Len(t, Tokenize("SELECT ?, ??, ???", c).Params(), 2)
+}
+
+func TestColonWordNotInBracketsTokenizing(t *testing.T) {
+	c := PostgreSQLConfig()
+	c.NoticeColonWord = true
+	c.ColonWordNotInBrackets = true
+	doTests(t, c, colonBracketCases)
+
+	c.ColonWordNotInBrackets = false
+	require.Equal(t, ColonWord, Tokenize("a[:n]", c)[2].Type)
+}
+
+func TestIdentifierExtraCharsTokenizing(t *testing.T) {
+	c := ANSIConfig()
+	c.IdentifierExtraChars = "$#"
+	doTests(t, c, extraCharsCases)
+
+	c = SQLServerConfig()
+	c.IdentifierExtraChars = "$"
+	require.Equal(t, Tokens{
+		{Type: Identifier, Text: "a$b"},
+	}, Tokenize("a$b", c), "NoticeIdentifiers takes precedence")
+}
+
+func TestDoubleSlashCommentTokenizing(t *testing.T) {
+	c := SQLServerConfig()
+	c.NoticeDoubleSlashComment = true
+	doTests(t, c, doubleSlashCases)
+}
+
+func TestBackslashGTokenizing(t *testing.T) {
+	c := MySQLConfig()
+	c.NoticeBackslashG = true
+	doTests(t, c, commonCases, backslashGCases)
+	require.Equal(t, []string{"SELECT 1", "SELECT 2"},
+		Tokenize("SELECT 1\\G\nSELECT 2 \\G", c).CmdSplit().Strings())
+}
+
 func TestOddbal1Tokenizing(t *testing.T) {
 	c := SQLServerConfig()
 	c.NoticeAtWord = false
@@ -1210,6 +3133,54 @@ func TestOddbal2Tokenizing(t *testing.T) {
 	doTests(t, c, commonCases, oddball2Cases)
 }
 
+func TestBackslashEscapesEverywhere(t *testing.T) {
+	escapes := OracleConfig()
+	escapes.NoticeCharsetLiteral = true
+	escapes.NoticeUAmpPrefix = true
+	noEscapes := escapes
+	noEscapes.NoBackslashEscapes = true
+	cases := []struct {
+		input    string
+		escaped  string
+		noEscape string
+	}{
+		{input: `'a\'b'`, escaped: `'a\'b'`, noEscape: `'a\'`},
+		{input: `"a\"b"`, escaped: `"a\"b"`, noEscape: `"a\"`},
+		{input: `N'a\'b'`, escaped: `N'a\'b'`, noEscape: `N'a\'`},
+		{input: `_utf8'a\'b'`, escaped: `_utf8'a\'b'`, noEscape: `_utf8'a\'`},
+		{input: `U&'a\'b'`, escaped: `U&'a\'b'`, noEscape: `U&'a\'`},
+		// q-strings never have escapes
+		{input: `q'!a\!'`, escaped: `q'!a\!'`, noEscape: `q'!a\!'`},
+	}
+	for _, tc := range cases {
+		ts := Tokenize(tc.input, escapes)
+		require.Equal(t, Literal, ts[0].Type, tc.input)
+		require.Equal(t, tc.escaped, ts[0].Text, tc.input)
+		ts = Tokenize(tc.input, noEscapes)
+		require.Equal(t, Literal, ts[0].Type, tc.input)
+		require.Equal(t, tc.noEscape, ts[0].Text, tc.input)
+	}
+}
+
+func TestBOM(t *testing.T) {
+	input := "\xEF\xBB\xBFSELECT 1"
+	for _, config := range []Config{MySQLConfig(), PostgreSQLConfig(), OracleConfig(), SQLServerConfig()} {
+		ts := Tokenize(input, config)
+		require.Equal(t, input, ts.String())
+		require.Equal(t, Tokens{
+			{Type: BOM, Text: "\xEF\xBB\xBF"},
+			{Type: Word, Text: "SELECT"},
+			{Type: Whitespace, Text: " "},
+			{Type: Number, Text: "1"},
+		}, ts)
+		require.Equal(t, "SELECT 1", ts.Strip().String())
+		require.Equal(t, []string{"SELECT 1"}, ts.CmdSplit().Strings())
+	}
+	// only a leading BOM is special
+	ts := TokenizeMySQL("SELECT \xEF\xBB\xBF")
+	require.Equal(t, Other, ts[len(ts)-1].Type)
+}
+
 func TestStrip(t *testing.T) {
 	cases := []struct {
 		before string
@@ -1227,13 +3198,56 @@ func TestStrip(t *testing.T) {
 			before: " /* foo */ bar \n baz  ; ",
 			after:  "bar baz",
 		},
+		{
+			before: "SELECT 1 -- trailing\n",
+			after:  "SELECT 1",
+		},
+		{
+			before: "SELECT 1; /* a */ /* b */ # c",
+			after:  "SELECT 1",
+		},
+		{
+			before: "SELECT a/* c */FROM t",
+			after:  "SELECT a FROM t",
+		},
+		{
+			before: "SELECT a /* c */ \n-- d\n FROM t",
+			after:  "SELECT a FROM t",
+		},
 	}
 	for _, tc := range cases {
 		ts := TokenizeMySQL(tc.before)
-		require.Equal(t, tc.after, ts.Strip().String(), tc.before)
+		stripped := ts.Strip()
+		require.Equal(t, tc.after, stripped.String(), tc.before)
+		require.Equal(t, stripped, stripped.Strip(), "idempotent: %s", tc.before)
 	}
 }
 
+func TestStripWith(t *testing.T) {
+	ts := TokenizeMySQL(" -- c\nSELECT a,\n\tb /* c */ FROM t ;\n")
+	require.Equal(t, "SELECT a, b FROM t", ts.StripWith(StripOpts{}).String())
+	require.Equal(t, "SELECT a, b FROM t", ts.StripWith(StripOpts{WhitespaceReplacement: " "}).String())
+	require.Equal(t, "SELECT\na,\nb\nFROM\nt", ts.StripWith(StripOpts{WhitespaceReplacement: "\n"}).String())
+	require.Equal(t, ts.Strip(), ts.StripWith(StripOpts{}))
+
+	keep := StripOpts{KeepTrailingDelimiter: true}
+	require.Equal(t, "SELECT 1", TokenizeMySQL("  SELECT 1 ;  ").StripWith(StripOpts{}).String())
+	require.Equal(t, "SELECT 1;", TokenizeMySQL("  SELECT 1 ;  ").StripWith(keep).String())
+	require.Equal(t, "SELECT 1;", TokenizeMySQL("SELECT 1;; -- c\n;").StripWith(keep).String())
+	require.Equal(t, "SELECT 1", TokenizeMySQL("SELECT 1 -- ;\n").StripWith(keep).String())
+	require.Equal(t, "", TokenizeMySQL(" ; ").StripWith(keep).String())
+	backslashG := MySQLConfig()
+	backslashG.NoticeBackslashG = true
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "SELECT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Number, Text: "1"},
+		{Type: Semicolon, Text: `\G`},
+	}, Tokenize("SELECT 1 \\G;\n", backslashG).StripWith(keep))
+	stripped := TokenizeMySQL("  SELECT 1 ;  ").StripWith(keep)
+	require.Equal(t, stripped, stripped.StripWith(keep), "idempotent")
+}
+
 func TestCmdSplit(t *testing.T) {
 	cases := []struct {
 		input string
@@ -1255,9 +3269,283 @@ func TestCmdSplit(t *testing.T) {
 			input: " /* foo */ bar \n ;baz  ; ",
 			want:  []string{"bar", "baz"},
 		},
+		{
+			input: ";; -- c\n; SELECT 1;;/* c */;SELECT 2",
+			want:  []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			input: "SELECT ';'; SELECT \";\" # ;\n",
+			want:  []string{"SELECT ';'", "SELECT \";\""},
+		},
+		{
+			input: "SELECT 1 /*!50000 ; SELECT '$$'; */; SELECT 2",
+			want:  []string{"SELECT 1", "SELECT 2"},
+		},
 	}
 	for _, tc := range cases {
 		ts := TokenizeMySQL(tc.input)
 		require.Equalf(t, tc.want, ts.CmdSplit().Strings(), tc.input)
+		require.Equalf(t, len(tc.want), ts.StatementCount(), tc.input)
+	}
+}
+
+func TestMaxTokens(t *testing.T) {
+	input := "SELECT " + strings.Repeat("a, ", 10000) + "b"
+	c := MySQLConfig()
+	c.MaxTokens = 5
+	ts := Tokenize(input, c)
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "SELECT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Truncated, Text: input[len("SELECT a, "):]},
+	}, ts)
+	require.Equal(t, input, ts.String())
+
+	c.MaxTokens = 3
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "SELECT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Literal, Text: "'a'"},
+		{Type: Truncated, Text: " -- b"},
+	}, Tokenize("SELECT 'a' -- b", c))
+	require.Equal(t, TokenizeMySQL("SELECT 'a'"), Tokenize("SELECT 'a'", c), "at the limit")
+	require.Equal(t, 30003, len(TokenizeMySQL(input)), "unlimited by default")
+}
+
+func TestCmdSplitWithLeadingComments(t *testing.T) {
+	cases := []struct {
+		input string
+		want  []string
+	}{
+		{
+			input: "",
+			want:  []string{},
+		},
+		{
+			input: "-- doc\nSELECT 1;\nSELECT 2;",
+			want:  []string{"-- doc\nSELECT 1", "SELECT 2"},
+		},
+		{
+			input: "SELECT 1; -- one\n\n/* two */\n-- more\nSELECT\n  2;;\n-- end\n",
+			want:  []string{"SELECT 1 -- one\n", "/* two */\n-- more\nSELECT\n  2\n-- end\n"},
+		},
+		{
+			input: "-- only a comment\n",
+			want:  []string{"-- only a comment\n"},
+		},
+		{
+			input: "SELECT 1; /* doc for\nnext */\nSELECT 2;",
+			want:  []string{"SELECT 1", "/* doc for\nnext */\nSELECT 2"},
+		},
+		{
+			input: "SELECT 1; /* a */ -- b\n-- doc\nSELECT 2;",
+			want:  []string{"SELECT 1 /* a */ -- b\n", "-- doc\nSELECT 2"},
+		},
+		{
+			input: "SELECT 1; /* a *//* doc\n */ SELECT 2;",
+			want:  []string{"SELECT 1 /* a */", "/* doc\n */ SELECT 2"},
+		},
+	}
+	for _, tc := range cases {
+		ts := TokenizeMySQL(tc.input)
+		require.Equalf(t, tc.want, ts.CmdSplitWithLeadingComments().Strings(), tc.input)
+		require.Equalf(t, tc.input, ts.String(), "receiver unchanged")
+	}
+}
+
+func TestCmdSplitUnstrippedAttachLeading(t *testing.T) {
+	cases := []struct {
+		input string
+		want  []string
+	}{
+		{
+			input: "",
+			want:  []string{},
+		},
+		{
+			input: "SELECT 1;  SELECT 2;",
+			want:  []string{"SELECT 1;", "  SELECT 2;"},
+		},
+		{
+			input: "-- doc\nSELECT 1 ;\n/* two */ SELECT 2;;\n-- end\n",
+			want:  []string{"-- doc\nSELECT 1 ;", "\n/* two */ SELECT 2;;\n-- end\n"},
+		},
+		{
+			input: "SELECT 1; SELECT 2",
+			want:  []string{"SELECT 1;", " SELECT 2"},
+		},
+		{
+			input: " -- only a comment\n",
+			want:  []string{" -- only a comment\n"},
+		},
+	}
+	for _, tc := range cases {
+		ts := TokenizeMySQL(tc.input)
+		got := ts.CmdSplitUnstrippedAttachLeading()
+		require.Equalf(t, tc.want, got.Strings(), tc.input)
+		require.Equalf(t, tc.input, strings.Join(got.Strings(), ""), "round trip")
 	}
 }
+
+func TestForEachCommand(t *testing.T) {
+	inputs := []string{
+		"",
+		"SELECT 1",
+		" ; SELECT 1 ;; -- c\n; SELECT 2",
+		"SELECT 1; /* c */ SELECT 2;",
+	}
+	semicolon := func(t Token) bool { return t.Type == Semicolon }
+	for _, input := range inputs {
+		ts := TokenizeMySQL(input)
+		var stripped, raw TokensList
+		require.NoError(t, ts.ForEachCommand(true, func(cmd Tokens) error {
+			stripped = append(stripped, cmd)
+			return nil
+		}))
+		require.NoError(t, ts.ForEachCommand(false, func(cmd Tokens) error {
+			raw = append(raw, cmd)
+			return nil
+		}))
+		require.Equal(t, ts.CmdSplit(), stripped, input)
+		require.Equal(t, ts.SplitOn(semicolon), raw, input)
+	}
+
+	var calls int
+	stop := errors.New("stop")
+	err := TokenizeMySQL("SELECT 1; SELECT 2; SELECT 3").ForEachCommand(true, func(cmd Tokens) error {
+		calls++
+		if cmd.String() == "SELECT 2" {
+			return stop
+		}
+		return nil
+	})
+	require.Equal(t, stop, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestSplitOn(t *testing.T) {
+	cases := []struct {
+		input      string
+		isBoundary func(Token) bool
+		want       []string
+	}{
+		{
+			input: "SELECT a FROM t UNION SELECT b FROM u union all SELECT 'union'",
+			isBoundary: func(t Token) bool {
+				return t.Type == Word && strings.EqualFold(t.Text, "UNION")
+			},
+			want: []string{"SELECT a FROM t ", " SELECT b FROM u ", " all SELECT 'union'"},
+		},
+		{
+			input: "SELECT 1; SELECT 2;;",
+			isBoundary: func(t Token) bool {
+				return t.Type == Semicolon
+			},
+			want: []string{"SELECT 1", " SELECT 2"},
+		},
+		{
+			input: "SELECT 1; SELECT 2; ;",
+			isBoundary: func(t Token) bool {
+				return t.Type == Semicolon
+			},
+			want: []string{"SELECT 1", " SELECT 2", " "},
+		},
+		{
+			input: "",
+			isBoundary: func(t Token) bool {
+				return t.Type == Semicolon
+			},
+			want: nil,
+		},
+	}
+	for _, tc := range cases {
+		var got []string
+		for _, ts := range TokenizeMySQL(tc.input).SplitOn(tc.isBoundary) {
+			got = append(got, ts.String())
+		}
+		require.Equal(t, tc.want, got, tc.input)
+	}
+}
+
+func FuzzTokenizeRoundTrip(f *testing.F) {
+	for _, tcl := range [][]Tokens{
+		commonCases,
+		mySQLCases,
+		postgreSQLCases,
+		oracleCases,
+		sqlServerCases,
+		cockroachCases,
+		hanaCases,
+		ansiCases,
+		informixCases,
+		h2Cases,
+		bigQueryCases,
+		backslashGCases,
+		doubleSlashCases,
+		tempTableCases,
+		typedLiteralCases,
+		extraCharsCases,
+		colonBracketCases,
+		escapedQuestionMarkCases,
+		dashDashCases,
+		asciiOnlyCases,
+		hexFloatCases,
+		digitSeparatorCases,
+		mySQLAnsiQuotesCases,
+		colonAtCases,
+		lineContinuationCases,
+		currencyCases,
+		pgQuestionMarkCases,
+		hashSpaceCases,
+		unterminatedDollarCases,
+		copyDataCases,
+		controlCharsCases,
+		separatePunctuationCases,
+		punctuationRunLimitCases,
+		oddball1Cases,
+		oddball2Cases,
+	} {
+		for _, tc := range tcl {
+			f.Add(tc.String())
+		}
+	}
+	configs := map[string]Config{
+		"zero":        {},
+		"ANSI":        ANSIConfig(),
+		"Oracle":      OracleConfig(),
+		"SQLServer":   SQLServerConfig(),
+		"MySQL":       MySQLConfig(),
+		"PostgreSQL":  PostgreSQLConfig(),
+		"CockroachDB": CockroachDBConfig(),
+		"HANA":        HANAConfig(),
+		"Informix":    InformixConfig(),
+		"H2":          H2Config(),
+		"BigQuery":    BigQueryConfig(),
+	}
+	// and one with every option turned on
+	var all Config
+	v := reflect.ValueOf(&all).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() == reflect.Bool {
+			v.Field(i).SetBool(true)
+		}
+	}
+	configs["all"] = all
+	limited := MySQLConfig()
+	limited.MaxTokens = 3
+	configs["MaxTokens"] = limited
+	runLimit := OracleConfig()
+	runLimit.PunctuationRunLimit = 1
+	configs["PunctuationRunLimit"] = runLimit
+	f.Fuzz(func(t *testing.T, s string) {
+		for name, config := range configs {
+			ts := Tokenize(s, config)
+			require.Equal(t, s, ts.String(), name)
+			require.Equal(t, len(ts.CmdSplit().Strings()), ts.StatementCount(), name)
+			require.Equal(t, s, strings.Join(ts.CmdSplitUnstrippedAttachLeading().Strings(), ""), name)
+		}
+	})
+}
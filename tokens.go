@@ -0,0 +1,931 @@
+package sqltoken
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// ByteLen returns the length, in bytes, of ts.String() without
+// building the string.
+func (ts Tokens) ByteLen() int {
+	var n int
+	for _, t := range ts {
+		n += len(t.Text)
+	}
+	return n
+}
+
+// Bytes returns ts.String() as a byte slice.  It is built directly
+// rather than by converting the string so there is only one copy.
+func (ts Tokens) Bytes() []byte {
+	b := make([]byte, 0, ts.ByteLen())
+	for _, t := range ts {
+		b = append(b, t.Text...)
+	}
+	return b
+}
+
+// TruncateBytes returns the longest prefix of ts whose ByteLen is
+// at most n.  Tokens are never split.  The bool is true if any
+// tokens were removed.
+func (ts Tokens) TruncateBytes(n int) (Tokens, bool) {
+	var total int
+	for i, t := range ts {
+		total += len(t.Text)
+		if total > n {
+			return ts[:i], true
+		}
+	}
+	return ts, false
+}
+
+// Lines returns the number of lines spanned by ts.String(): the
+// count of newlines plus one.
+func (ts Tokens) Lines() int {
+	n := 1
+	for _, t := range ts {
+		n += strings.Count(t.Text, "\n")
+	}
+	return n
+}
+
+// LineAt returns the 1-based line number on which ts[tokenIndex]
+// starts and the full text of that line from ts.String(), without
+// its line ending.  A token that spans lines is reported at its first
+// line.  If tokenIndex is out of range, LineAt returns 0 and "".
+func (ts Tokens) LineAt(tokenIndex int) (line int, text string) {
+	if tokenIndex < 0 || tokenIndex >= len(ts) {
+		return 0, ""
+	}
+	s := ts.String()
+	var start int
+	for _, t := range ts[:tokenIndex] {
+		start += len(t.Text)
+	}
+	line = strings.Count(s[:start], "\n") + 1
+	begin := strings.LastIndexByte(s[:start], '\n') + 1
+	end := strings.IndexByte(s[start:], '\n')
+	if end == -1 {
+		end = len(s)
+	} else {
+		end += start
+	}
+	return line, strings.TrimSuffix(s[begin:end], "\r")
+}
+
+// isWhitespaceOnly returns true if ts has nothing other than
+// whitespace, comments, and byte order marks
+func (ts Tokens) isWhitespaceOnly() bool {
+	for _, t := range ts {
+		// nolint:exhaustive
+		switch t.Type {
+		case Whitespace, Comment, BOM:
+			continue
+		}
+		if t.Text != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty returns true if ts has no tokens at all
+func (ts Tokens) IsEmpty() bool {
+	return len(ts) == 0
+}
+
+// IsBlank returns true if ts has nothing other than whitespace,
+// comments, byte order marks, and empty tokens.  An empty ts
+// is blank.
+func (ts Tokens) IsBlank() bool {
+	return ts.isWhitespaceOnly()
+}
+
+// FilterNonEmpty returns the segments of tl that contain at least
+// one token that is not whitespace or a comment.  Unlike Strings(),
+// the result is still a TokensList.
+func (tl TokensList) FilterNonEmpty() TokensList {
+	r := make(TokensList, 0, len(tl))
+	for _, ts := range tl {
+		if !ts.isWhitespaceOnly() {
+			r = append(r, ts)
+		}
+	}
+	return r
+}
+
+// TrimSpace returns a sub-slice of ts without leading or trailing
+// whitespace and comments.  Unlike Strip, the tokens in the middle
+// are untouched.
+func (ts Tokens) TrimSpace() Tokens {
+	start := 0
+	for start < len(ts) && (ts[start].Type == Whitespace || ts[start].Type == Comment) {
+		start++
+	}
+	end := len(ts)
+	for end > start && (ts[end-1].Type == Whitespace || ts[end-1].Type == Comment) {
+		end--
+	}
+	return ts[start:end]
+}
+
+// Between returns the tokens strictly between the first Word that
+// matches startWord and the next Word after it that matches endWord.
+// Matching is case-insensitive.  Between returns nil if either word
+// is missing.  The result is a sub-slice of ts and is not stripped:
+// use TrimSpace to remove the surrounding whitespace.
+func (ts Tokens) Between(startWord, endWord string) Tokens {
+	start := -1
+	for i, t := range ts {
+		if t.Type != Word {
+			continue
+		}
+		if start == -1 {
+			if strings.EqualFold(t.Text, startWord) {
+				start = i
+			}
+		} else if strings.EqualFold(t.Text, endWord) {
+			return ts[start+1 : i]
+		}
+	}
+	return nil
+}
+
+// EditEach calls fn with a pointer to each token in ts so that fn
+// can change the token in place.  Unlike methods that return a new
+// Tokens, EditEach mutates the receiver: use it only when the caller
+// owns ts.
+func (ts Tokens) EditEach(fn func(i int, t *Token)) {
+	for i := range ts {
+		fn(i, &ts[i])
+	}
+}
+
+// ReplaceType returns a copy of ts where the tokens of type from
+// for which when returns true have been changed to type to.  A nil
+// when matches every token of type from.
+func (ts Tokens) ReplaceType(from, to TokenType, when func(Token) bool) Tokens {
+	c := make(Tokens, len(ts))
+	for i, t := range ts {
+		if t.Type == from && (when == nil || when(t)) {
+			t.Type = to
+		}
+		c[i] = t
+	}
+	return c
+}
+
+// TokenPredicate matches one token, see ReplaceSequence
+type TokenPredicate func(Token) bool
+
+// ReplaceSequence returns a copy of ts where each run of tokens that
+// matches the predicates in match, one token per predicate, has been
+// replaced by replacement(run).  Runs are found from left to right and
+// do not overlap.  Whitespace is not skipped: it must be matched like
+// any other token.  The tokens on either side of a replacement are
+// not merged.  If match is empty, ts is copied unchanged.
+func (ts Tokens) ReplaceSequence(match []TokenPredicate, replacement func([]Token) []Token) Tokens {
+	c := make(Tokens, 0, len(ts))
+	i := 0
+	for i < len(ts) {
+		if len(match) == 0 || i+len(match) > len(ts) || !matchSequence(ts[i:i+len(match)], match) {
+			c = append(c, ts[i])
+			i++
+			continue
+		}
+		c = append(c, replacement(ts[i:i+len(match)])...)
+		i += len(match)
+	}
+	return c
+}
+
+func matchSequence(ts Tokens, match []TokenPredicate) bool {
+	for i, m := range match {
+		if !m(ts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// CommentInfo describes one Comment token, see Comments
+type CommentInfo struct {
+	// Index is the position of the comment in ts
+	Index int
+	// Style is "block" for /* */ and { } comments and "line" for
+	// comments that run to the end of the line, like -- and #
+	Style string
+	Text  string
+}
+
+// Comments returns a CommentInfo for each Comment token in ts.  Since
+// Tokenize merges adjacent comments into one token, the Style of such
+// a token comes from the first comment in it.
+func (ts Tokens) Comments() []CommentInfo {
+	var r []CommentInfo
+	for i, t := range ts {
+		if t.Type != Comment {
+			continue
+		}
+		style := "line"
+		if strings.HasPrefix(t.Text, "/*") || strings.HasPrefix(t.Text, "{") {
+			style = "block"
+		}
+		r = append(r, CommentInfo{
+			Index: i,
+			Style: style,
+			Text:  t.Text,
+		})
+	}
+	return r
+}
+
+// MapComments returns a copy of ts where the text of each Comment
+// has been replaced by f(text).  Comments for which f returns "" are
+// dropped and the tokens on either side are merged if Tokenize would
+// have merged them.  Where dropping a comment would join the tokens
+// on either side, as in a/*x*/b, it is replaced by whitespace instead.
+// Optimizer hints are not comments and are left alone.
+func (ts Tokens) MapComments(f func(text string) string) Tokens {
+	c := make(Tokens, 0, len(ts))
+	var dropped bool
+	for i, t := range ts {
+		if t.Type == Comment {
+			t.Text = f(t.Text)
+			if t.Text == "" {
+				if len(c) > 0 && c[len(c)-1].Type != Whitespace && i+1 < len(ts) && ts[i+1].Type != Whitespace {
+					t = Token{Type: Whitespace, Text: " "}
+					if strings.HasSuffix(ts[i].Text, "\n") {
+						t.Text = "\n"
+					}
+				} else {
+					dropped = true
+					continue
+				}
+			}
+		}
+		if dropped && len(c) > 0 && c[len(c)-1].Type == t.Type && combineOkay(t.Type) {
+			c[len(c)-1].Text += t.Text
+		} else {
+			c = append(c, t)
+		}
+		dropped = false
+	}
+	return c
+}
+
+// RemoveHints returns a copy of ts without optimizer hints.  Both
+// Hint tokens and comments that look like hints (/*+ ... */ and
+// --+ ...) are removed so the result does not depend upon
+// NoticeOptimizerHints.  Other comments are kept.  Where removing a
+// hint would join the tokens on either side, it is replaced by
+// whitespace instead.
+func (ts Tokens) RemoveHints() Tokens {
+	c := make(Tokens, 0, len(ts))
+	var dropped bool
+	for i, t := range ts {
+		if t.Type == Comment {
+			t.Text = withoutHintComments(t.Text)
+		}
+		if t.Type == Hint || t.Text == "" {
+			if len(c) > 0 && c[len(c)-1].Type != Whitespace && i+1 < len(ts) && ts[i+1].Type != Whitespace {
+				t = Token{Type: Whitespace, Text: " "}
+				if strings.HasSuffix(ts[i].Text, "\n") {
+					t.Text = "\n"
+				}
+			} else {
+				dropped = true
+				continue
+			}
+		}
+		if dropped && len(c) > 0 && c[len(c)-1].Type == t.Type && combineOkay(t.Type) {
+			c[len(c)-1].Text += t.Text
+		} else {
+			c = append(c, t)
+		}
+		dropped = false
+	}
+	return c
+}
+
+// withoutHintComments returns text, which is made up of one or more
+// adjacent comments, without the comments that look like hints
+func withoutHintComments(text string) string {
+	var b strings.Builder
+	for _, comment := range splitComments(text) {
+		if !strings.HasPrefix(comment, "/*+") && !strings.HasPrefix(comment, "--+") {
+			b.WriteString(comment)
+		}
+	}
+	return b.String()
+}
+
+// splitComments breaks up the text of a Comment token, which Tokenize
+// may have merged from several adjacent comments, into the individual
+// comments.  A nested comment (NestedComments) is kept whole.  Where
+// comments do not nest, the inner /* leave the nesting unbalanced so
+// the first */ ends the comment.
+func splitComments(text string) []string {
+	var r []string
+	for text != "" {
+		var end int
+		if strings.HasPrefix(text, "/*") {
+			end = nestedCommentEnd(text)
+			if end == -1 {
+				end = strings.Index(text[2:], "*/") + 4
+			}
+			if end == 3 {
+				end = len(text)
+			}
+		} else {
+			end = strings.IndexByte(text, '\n') + 1
+			if end == 0 {
+				end = len(text)
+			}
+		}
+		r = append(r, text[:end])
+		text = text[end:]
+	}
+	return r
+}
+
+// nestedCommentEnd returns the length of the nested /* ... */ comment
+// at the start of text or -1 if it is not closed
+func nestedCommentEnd(text string) int {
+	var depth int
+	for i := 0; i+1 < len(text); i++ {
+		switch text[i : i+2] {
+		case "/*":
+			depth++
+			i++
+		case "*/":
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// MySQLVersionGates returns the version from each MySQL executable
+// comment in ts, in order: /*!80000 ... */ gives 80000.  An executable
+// comment without a version, /*! ... */, gives 0 since it applies to
+// every version.  Other comments are ignored.
+func (ts Tokens) MySQLVersionGates() []int {
+	var r []int
+	for _, t := range ts {
+		if t.Type != Comment {
+			continue
+		}
+		for _, comment := range splitComments(t.Text) {
+			if !strings.HasPrefix(comment, "/*!") {
+				continue
+			}
+			var version int
+			for _, c := range []byte(comment[3:]) {
+				if !isDigit(c) {
+					break
+				}
+				version = version*10 + int(c-'0')
+			}
+			r = append(r, version)
+		}
+	}
+	return r
+}
+
+// Equal returns true if ts and other have the same tokens: the
+// same Type and Text in the same order.  Meta is not compared.
+func (ts Tokens) Equal(other Tokens) bool {
+	if len(ts) != len(other) {
+		return false
+	}
+	for i, t := range ts {
+		if t.Type != other[i].Type || t.Text != other[i].Text {
+			return false
+		}
+	}
+	return true
+}
+
+// SemanticEqual reports whether ts and other are Equal once
+// whitespace, comments, and byte order marks are removed.  Everything
+// else, including literals and the case of words, must match.  Since
+// the removed tokens are what keep punctuation apart, adjacent
+// Punctuation is compared as one token so that "(1),(2)" matches
+// "(1), (2)".
+func (ts Tokens) SemanticEqual(other Tokens) bool {
+	return ts.semantic().Equal(other.semantic())
+}
+
+func (ts Tokens) semantic() Tokens {
+	c := make(Tokens, 0, len(ts))
+	for _, t := range ts {
+		// nolint:exhaustive
+		switch t.Type {
+		case Whitespace, Comment, BOM:
+			continue
+		case Punctuation:
+			if len(c) > 0 && c[len(c)-1].Type == Punctuation {
+				c[len(c)-1].Text += t.Text
+				continue
+			}
+		}
+		c = append(c, Token{Type: t.Type, Text: t.Text})
+	}
+	return c
+}
+
+// Hash returns an FNV-1a hash of the Type and Text of each token.
+// Tokens that are Equal have the same Hash.
+func (ts Tokens) Hash() uint64 {
+	h := fnv.New64a()
+	var buf [binary.MaxVarintLen64]byte
+	for _, t := range ts {
+		// The length prefix keeps "ab","c" distinct from "a","bc"
+		n := binary.PutUvarint(buf[:], uint64(len(t.Text)))
+		_, _ = h.Write([]byte{byte(t.Type)})
+		_, _ = h.Write(buf[:n])
+		_, _ = h.Write([]byte(t.Text))
+	}
+	return h.Sum64()
+}
+
+// ValidateBalanced returns an error describing the first problem
+// found in ts: an Unterminated token, a ) ] or } that does not match
+// the most recent opener, or a ( [ or { that is never closed.  Only
+// Punctuation tokens are examined for brackets so brackets inside
+// strings and comments do not count.
+func (ts Tokens) ValidateBalanced() error {
+	type opener struct {
+		c     byte
+		token int
+	}
+	var open []opener
+	for i, t := range ts {
+		if t.Unterminated {
+			return fmt.Errorf("unterminated %s at token %d", t.Type, i)
+		}
+		if t.Type != Punctuation {
+			continue
+		}
+		for j := 0; j < len(t.Text); j++ {
+			c := t.Text[j]
+			var want byte
+			switch c {
+			case '(', '[', '{':
+				open = append(open, opener{c: c, token: i})
+				continue
+			case ')':
+				want = '('
+			case ']':
+				want = '['
+			case '}':
+				want = '{'
+			default:
+				continue
+			}
+			if len(open) == 0 || open[len(open)-1].c != want {
+				return fmt.Errorf("unbalanced %q at token %d", c, i)
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) != 0 {
+		o := open[len(open)-1]
+		return fmt.Errorf("unclosed %q at token %d", o.c, o.token)
+	}
+	return nil
+}
+
+// Concat returns a new Tokens that is ts followed by other.  If the
+// token types at the seam are the same and are a type that Tokenize
+// merges (like Whitespace or Comment), the two are merged into one
+// token.  Neither ts nor other is modified.
+func (ts Tokens) Concat(other Tokens) Tokens {
+	c := make(Tokens, len(ts), len(ts)+len(other))
+	copy(c, ts)
+	if len(c) > 0 && len(other) > 0 && c[len(c)-1].Type == other[0].Type && combineOkay(other[0].Type) {
+		c[len(c)-1].Text += other[0].Text
+		other = other[1:]
+	}
+	return append(c, other...)
+}
+
+// String returns the type and quoted text of t, like Word("SELECT").
+// Use Tokens.String to reconstruct SQL.
+func (t Token) String() string {
+	return fmt.Sprintf("%s(%q)", t.Type, t.Text)
+}
+
+// MatchParen returns the index of the token holding the ) that
+// matches the ( in ts[openIndex].  Since adjacent punctuation is
+// combined, a token may hold several parenthesis: counting starts from
+// the first ( in ts[openIndex].  ok is false if ts[openIndex] has no
+// ( or if the ( is never closed.
+func (ts Tokens) MatchParen(openIndex int) (closeIndex int, ok bool) {
+	if openIndex < 0 || openIndex >= len(ts) || ts[openIndex].Type != Punctuation {
+		return 0, false
+	}
+	start := strings.IndexByte(ts[openIndex].Text, '(')
+	if start == -1 {
+		return 0, false
+	}
+	var depth int
+	for i := openIndex; i < len(ts); i++ {
+		if ts[i].Type != Punctuation {
+			continue
+		}
+		text := ts[i].Text
+		if i == openIndex {
+			text = text[start:]
+		}
+		for j := 0; j < len(text); j++ {
+			switch text[j] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					return i, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// InsertAt returns a new Tokens with insert placed before ts[index].
+// Like Concat, tokens are merged at both seams where Tokenize would
+// have merged them.  ts is not modified.  InsertAt panics if index is
+// out of range.
+func (ts Tokens) InsertAt(index int, insert ...Token) Tokens {
+	return ts[:index].Concat(insert).Concat(ts[index:])
+}
+
+// Prepend returns a new Tokens with insert placed before ts
+func (ts Tokens) Prepend(insert ...Token) Tokens {
+	return Tokens(insert).Concat(ts)
+}
+
+// CollapseInLists returns a copy of ts where each IN (...) list of
+// values is replaced by IN (?) so that queries that differ only in
+// the length of their IN lists look the same.  A list qualifies only
+// if it holds nothing but literals, numbers, parameter markers, NULL,
+// TRUE, FALSE, commas, and signs.  Subqueries like IN (SELECT ...) are
+// left alone.
+func (ts Tokens) CollapseInLists() Tokens {
+	c := make(Tokens, 0, len(ts))
+	for i := 0; i < len(ts); i++ {
+		c = append(c, ts[i])
+		if ts[i].Type != Word || !strings.EqualFold(ts[i].Text, "IN") {
+			continue
+		}
+		open := i + 1
+		for open < len(ts) && (ts[open].Type == Whitespace || ts[open].Type == Comment) {
+			open++
+		}
+		closeIndex, closeRest, ok := ts.inListEnd(open)
+		if !ok {
+			continue
+		}
+		c = append(c, ts[i+1:open]...)
+		c = append(c,
+			Token{Type: Punctuation, Text: "("},
+			Token{Type: QuestionMark, Text: "?"},
+			Token{Type: Punctuation, Text: closeRest},
+		)
+		i = closeIndex
+	}
+	return c
+}
+
+// inListEnd checks that ts[open] starts a list of values.  It returns
+// the index of the token with the closing ) and the text of that
+// token starting from the ).
+func (ts Tokens) inListEnd(open int) (int, string, bool) {
+	if open >= len(ts) || ts[open].Type != Punctuation || !strings.HasPrefix(ts[open].Text, "(") {
+		return 0, "", false
+	}
+	for i := open; i < len(ts); i++ {
+		t := ts[i]
+		// nolint:exhaustive
+		switch t.Type {
+		case Literal, Number, QuestionMark, DollarNumber, ColonWord, ColonNumber, AtWord, TypedLiteral, Money, Whitespace, Comment:
+			continue
+		case Word:
+			switch strings.ToUpper(t.Text) {
+			case "NULL", "TRUE", "FALSE":
+				continue
+			}
+			return 0, "", false
+		case Punctuation:
+			start := 0
+			if i == open {
+				start = 1
+			}
+			for j := start; j < len(t.Text); j++ {
+				switch t.Text[j] {
+				case ',', '-', '+':
+				case ')':
+					return i, t.Text[j:], true
+				default:
+					return 0, "", false
+				}
+			}
+		default:
+			return 0, "", false
+		}
+	}
+	return 0, "", false
+}
+
+// ComplexityWeights are the points given to each of the things that
+// ComplexityWith counts
+type ComplexityWeights struct {
+	Join         int // each JOIN
+	Subquery     int // each ( SELECT or ( WITH
+	Union        int // each UNION, INTERSECT, or EXCEPT
+	FunctionCall int // each word, other than a keyword, followed by (
+}
+
+// Complexity returns a rough measure of how expensive the query in ts
+// may be to run: ComplexityWith using a weight of 2 for subqueries and
+// 1 for everything else.
+func (ts Tokens) Complexity() int {
+	return ts.ComplexityWith(ComplexityWeights{
+		Join:         1,
+		Subquery:     2,
+		Union:        1,
+		FunctionCall: 1,
+	})
+}
+
+// ComplexityWith returns the weighted count of joins, subqueries,
+// unions, and function calls in ts.  It is a heuristic that only
+// looks at the tokens: it does not parse the SQL.
+func (ts Tokens) ComplexityWith(w ComplexityWeights) int {
+	var score int
+	for i, t := range ts {
+		next := i + 1
+		for next < len(ts) && (ts[next].Type == Whitespace || ts[next].Type == Comment) {
+			next++
+		}
+		// nolint:exhaustive
+		switch t.Type {
+		case Word:
+			switch strings.ToUpper(t.Text) {
+			case "JOIN":
+				score += w.Join
+			case "UNION", "INTERSECT", "EXCEPT":
+				score += w.Union
+			default:
+				if !isKeyword(t.Text) && i+1 < len(ts) && ts[i+1].Type == Punctuation && ts[i+1].Text[0] == '(' {
+					score += w.FunctionCall
+				}
+			}
+		case Punctuation:
+			if strings.HasSuffix(t.Text, "(") && next < len(ts) && ts[next].Type == Word &&
+				(strings.EqualFold(ts[next].Text, "SELECT") || strings.EqualFold(ts[next].Text, "WITH")) {
+				score += w.Subquery
+			}
+		}
+	}
+	return score
+}
+
+// RenameIdentifiers returns a copy of ts where each Word or
+// Identifier whose name is a key in mapping has been replaced by the
+// mapped value.  Names are compared case-sensitively.  Quoted
+// identifiers ("x", [x], and `x`) are compared without their quotes
+// and the new name is quoted the same way.  `x` is only an Identifier
+// with NoticeBacktickIdentifiers.  To rename a qualified name like
+// s.t, rename its parts.
+func (ts Tokens) RenameIdentifiers(mapping map[string]string) Tokens {
+	return ts.renameIdentifiers(func(name string) (string, bool) {
+		to, ok := mapping[name]
+		return to, ok
+	})
+}
+
+// RenameIdentifiersFold is like RenameIdentifiers but compares names
+// case-insensitively.  If several keys of mapping differ only in case,
+// a key that matches a name exactly wins; otherwise the key that sorts
+// first is used.
+func (ts Tokens) RenameIdentifiersFold(mapping map[string]string) Tokens {
+	keys := make([]string, 0, len(mapping))
+	for from := range mapping {
+		keys = append(keys, from)
+	}
+	sort.Strings(keys)
+	folded := make(map[string]string, len(mapping))
+	for _, from := range keys {
+		lower := strings.ToLower(from)
+		if _, ok := folded[lower]; !ok {
+			folded[lower] = mapping[from]
+		}
+	}
+	return ts.renameIdentifiers(func(name string) (string, bool) {
+		if to, ok := mapping[name]; ok {
+			return to, true
+		}
+		to, ok := folded[strings.ToLower(name)]
+		return to, ok
+	})
+}
+
+func (ts Tokens) renameIdentifiers(lookup func(string) (string, bool)) Tokens {
+	c := make(Tokens, len(ts))
+	for i, t := range ts {
+		c[i] = t
+		if (t.Type != Word && t.Type != Identifier) || t.Unterminated {
+			continue
+		}
+		open, closer, name := unquoteIdentifier(t.Text)
+		to, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if closer != "" {
+			to = open + strings.ReplaceAll(to, closer, closer+closer) + closer
+		}
+		c[i].Text = to
+	}
+	return c
+}
+
+// unquoteIdentifier splits a possibly quoted identifier into its
+// quotes and its name
+func unquoteIdentifier(text string) (open, closer, name string) {
+	if len(text) < 2 {
+		return "", "", text
+	}
+	switch text[0] {
+	case '"', '`':
+		open, closer = text[:1], text[:1]
+	case '[':
+		open, closer = "[", "]"
+	default:
+		return "", "", text
+	}
+	if !strings.HasSuffix(text, closer) {
+		return "", "", text
+	}
+	name = strings.ReplaceAll(text[1:len(text)-1], closer+closer, closer)
+	return open, closer, name
+}
+
+// SetMeta attaches a key/value annotation to t, allocating Meta
+// if needed.
+func (t *Token) SetMeta(k string, v any) {
+	if t.Meta == nil {
+		t.Meta = make(map[string]any)
+	}
+	t.Meta[k] = v
+}
+
+// GetMeta returns the annotation stored with SetMeta
+func (t Token) GetMeta(k string) (any, bool) {
+	v, ok := t.Meta[k]
+	return v, ok
+}
+
+// Copy returns a copy of t that does not share Meta with t.  The
+// values in Meta are not copied.
+func (t Token) Copy() Token {
+	if t.Meta != nil {
+		m := make(map[string]any, len(t.Meta))
+		for k, v := range t.Meta {
+			m[k] = v
+		}
+		t.Meta = m
+	}
+	return t
+}
+
+// Copy returns a copy of ts where each token has been copied
+// with Token.Copy.
+func (ts Tokens) Copy() Tokens {
+	c := make(Tokens, len(ts))
+	for i, t := range ts {
+		c[i] = t.Copy()
+	}
+	return c
+}
+
+// DumpString returns one line per token, in the Token.String() form
+// Word("SELECT"), for use in golden tests.  Unterminated tokens are
+// followed by " unterminated".  The format is meant to be stable.
+func DumpString(ts Tokens) string {
+	var b strings.Builder
+	for _, t := range ts {
+		b.WriteString(t.String())
+		if t.Unterminated {
+			b.WriteString(" unterminated")
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Diff returns a line-per-token unified diff from ts to other.  Lines
+// for tokens only in ts start with "-", lines for tokens only in other
+// start with "+", and lines for tokens in both start with " ".  Tokens
+// are compared by Type and Text, as in Equal.  Diff returns "" if ts
+// and other are Equal.  It is meant as a debugging aid and takes time
+// proportional to len(ts) * len(other).
+func (ts Tokens) Diff(other Tokens) string {
+	if ts.Equal(other) {
+		return ""
+	}
+	same := func(i, j int) bool {
+		return ts[i].Type == other[j].Type && ts[i].Text == other[j].Text
+	}
+	// lcs[i][j] is the length of the longest common subsequence
+	// of ts[i:] and other[j:]
+	lcs := make([][]int, len(ts)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(other)+1)
+	}
+	for i := len(ts) - 1; i >= 0; i-- {
+		for j := len(other) - 1; j >= 0; j-- {
+			switch {
+			case same(i, j):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var b strings.Builder
+	var i, j int
+	for i < len(ts) || j < len(other) {
+		switch {
+		case i < len(ts) && j < len(other) && same(i, j):
+			b.WriteString(" " + ts[i].String() + "\n")
+			i++
+			j++
+		case j == len(other) || (i < len(ts) && lcs[i+1][j] >= lcs[i][j+1]):
+			b.WriteString("-" + ts[i].String() + "\n")
+			i++
+		default:
+			b.WriteString("+" + other[j].String() + "\n")
+			j++
+		}
+	}
+	return b.String()
+}
+
+// Canonicalize returns Tokens that have the same text as ts except
+// that whitespace has been inserted where two adjacent tokens would
+// otherwise be tokenized as something else: SELECT and a become
+// SELECT a rather than SELECTa.  The result is tokenized with cfg so
+// Tokenize(result.String(), cfg) is Equal to the result.  Tokens whose
+// text is not a single token to begin with are re-split.  Meta is
+// not preserved.
+func (ts Tokens) Canonicalize(cfg Config) Tokens {
+	var b strings.Builder
+	for i, t := range ts {
+		if i > 0 {
+			b.WriteString(separator(ts[i-1], t, cfg))
+		}
+		b.WriteString(t.Text)
+	}
+	return Tokenize(b.String(), cfg)
+}
+
+// separator returns the whitespace, if any, that must go between a
+// and b so that they are tokenized as a followed by b
+func separator(a, b Token, cfg Config) string {
+	if a.Type == Whitespace || b.Type == Whitespace || a.Text == "" || b.Text == "" {
+		return ""
+	}
+	// Merging punctuation or comments doesn't change their meaning
+	// but merging words, literals, or identifiers does
+	want := Tokens{a, b}
+	if a.Type == b.Type && (a.Type == Punctuation || a.Type == Comment) {
+		want = Tokens{a}.Concat(Tokens{b})
+	}
+	if Tokenize(a.Text+b.Text, cfg).Equal(want) {
+		return ""
+	}
+	for _, sep := range []string{" ", "\n"} {
+		// The separator may become part of a, as the newline does
+		// for a -- comment
+		got := Tokenize(a.Text+sep+b.Text, cfg)
+		last := len(got) - 1
+		if last > 0 && got[0].Type == a.Type && strings.HasPrefix(got[0].Text, a.Text) &&
+			got[last].Type == b.Type && got[last].Text == b.Text &&
+			got[:last].String() == a.Text+sep {
+			return sep
+		}
+	}
+	return " "
+}
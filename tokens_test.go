@@ -0,0 +1,731 @@
+package sqltoken
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestByteLenAndLines(t *testing.T) {
+	cases := []string{
+		"",
+		"SELECT 1",
+		"SELECT 1;\nSELECT 2;\n",
+		"-- comment\nSELECT 'multi\nline'\n/* block\n */ FROM t",
+		"SELECT 'ǝè' FROM \"ᴛ\"",
+	}
+	for _, input := range cases {
+		ts := TokenizeMySQL(input)
+		require.Equal(t, len(ts.String()), ts.ByteLen(), input)
+		require.Equal(t, strings.Count(input, "\n")+1, ts.Lines(), input)
+	}
+}
+
+func TestLineAt(t *testing.T) {
+	ts := TokenizeMySQL("SELECT a,\r\n  b\nFROM t /* x\ny */ WHERE\n\tbad = 'multi\nline'")
+	find := func(text string) int {
+		for i, tok := range ts {
+			if tok.Text == text {
+				return i
+			}
+		}
+		t.Fatalf("no token %q", text)
+		return -1
+	}
+	cases := []struct {
+		token string
+		line  int
+		text  string
+	}{
+		{token: "SELECT", line: 1, text: "SELECT a,"},
+		{token: "b", line: 2, text: "  b"},
+		{token: "FROM", line: 3, text: "FROM t /* x"},
+		{token: "/* x\ny */", line: 3, text: "FROM t /* x"},
+		{token: "WHERE", line: 4, text: "y */ WHERE"},
+		{token: "bad", line: 5, text: "\tbad = 'multi"},
+		{token: "'multi\nline'", line: 5, text: "\tbad = 'multi"},
+	}
+	for _, tc := range cases {
+		line, text := ts.LineAt(find(tc.token))
+		require.Equal(t, tc.line, line, tc.token)
+		require.Equal(t, tc.text, text, tc.token)
+	}
+	line, text := ts.LineAt(len(ts))
+	require.Equal(t, 0, line)
+	require.Equal(t, "", text)
+	line, _ = ts.LineAt(-1)
+	require.Equal(t, 0, line)
+}
+
+func TestBytes(t *testing.T) {
+	for _, tc := range commonCases {
+		ts := TokenizeMySQL(tc.String())
+		require.Equal(t, []byte(ts.String()), ts.Bytes(), tc.String())
+	}
+	require.Empty(t, Tokens{}.Bytes())
+}
+
+func TestTruncateBytes(t *testing.T) {
+	ts := TokenizeMySQL("SELECT 'a long string' FROM t")
+	cases := []struct {
+		n         int
+		want      string
+		truncated bool
+	}{
+		{n: 100, want: "SELECT 'a long string' FROM t"},
+		{n: len(ts.String()), want: "SELECT 'a long string' FROM t"},
+		{n: len(ts.String()) - 1, want: "SELECT 'a long string' FROM ", truncated: true},
+		{n: 7, want: "SELECT ", truncated: true},
+		{n: 10, want: "SELECT ", truncated: true},
+		{n: 0, want: "", truncated: true},
+	}
+	for _, tc := range cases {
+		got, truncated := ts.TruncateBytes(tc.n)
+		require.Equal(t, tc.want, got.String(), tc.n)
+		require.Equal(t, tc.truncated, truncated, tc.n)
+		require.LessOrEqual(t, got.ByteLen(), tc.n)
+	}
+	got, truncated := Tokens{}.TruncateBytes(0)
+	require.Empty(t, got)
+	require.False(t, truncated)
+}
+
+func TestFilterNonEmpty(t *testing.T) {
+	split := TokenizeMySQL(" ; SELECT 1 ;; -- c\n; SELECT 2").CmdSplit()
+	require.Len(t, split, 4)
+	filtered := split.FilterNonEmpty()
+	require.Equal(t, []string{"SELECT 1", "SELECT 2"}, filtered.Strings())
+	require.Len(t, filtered, 2)
+
+	unstripped := TokensList{
+		TokenizeMySQL("  \n\t"),
+		TokenizeMySQL("SELECT 1"),
+		TokenizeMySQL(" -- just a comment\n"),
+		TokenizeMySQL("/* c */ SELECT 2 "),
+		{},
+	}
+	require.Equal(t, TokensList{unstripped[1], unstripped[3]}, unstripped.FilterNonEmpty())
+}
+
+func TestIsEmptyAndIsBlank(t *testing.T) {
+	cases := []struct {
+		input string
+		empty bool
+		blank bool
+	}{
+		{input: "", empty: true, blank: true},
+		{input: " \n\t", blank: true},
+		{input: "-- comment\n/* another */", blank: true},
+		{input: "SELECT 1", blank: false},
+		{input: " /* c */ ;", blank: false},
+	}
+	for _, tc := range cases {
+		ts := TokenizeMySQL(tc.input)
+		require.Equal(t, tc.empty, ts.IsEmpty(), tc.input)
+		require.Equal(t, tc.blank, ts.IsBlank(), tc.input)
+	}
+	require.True(t, Tokens{{Type: Word, Text: ""}}.IsBlank())
+	require.False(t, Tokens{{Type: Word, Text: ""}}.IsEmpty())
+}
+
+func TestTrimSpace(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "",
+			want:  "",
+		},
+		{
+			input: " \n-- comment\n",
+			want:  "",
+		},
+		{
+			input: "SELECT  1",
+			want:  "SELECT  1",
+		},
+		{
+			input: " /* c */ SELECT  /* keep */\n1 ; -- trailing\n ",
+			want:  "SELECT  /* keep */\n1 ;",
+		},
+	}
+	for _, tc := range cases {
+		ts := TokenizeMySQL(tc.input)
+		require.Equal(t, tc.want, ts.TrimSpace().String(), tc.input)
+	}
+	ts := TokenizeMySQL("SELECT 1")
+	require.Equal(t, ts, ts.TrimSpace())
+}
+
+func TestEditEach(t *testing.T) {
+	ts := TokenizeMySQL("SELECT\ta,\n  b   FROM t")
+	var seen []int
+	ts.EditEach(func(i int, t *Token) {
+		seen = append(seen, i)
+		if t.Type == Whitespace {
+			t.Text = " "
+		}
+	})
+	require.Equal(t, "SELECT a, b FROM t", ts.String())
+	require.Len(t, seen, len(ts))
+	for i, n := range seen {
+		require.Equal(t, i, n)
+	}
+}
+
+func TestReplaceType(t *testing.T) {
+	ts := TokenizeMySQL("SELECT name, user FROM t")
+	names := map[string]bool{"name": true, "user": true}
+	got := ts.ReplaceType(Word, Identifier, func(t Token) bool {
+		return names[t.Text]
+	})
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "SELECT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "name"},
+		{Type: Punctuation, Text: ","},
+		{Type: Whitespace, Text: " "},
+		{Type: Identifier, Text: "user"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "FROM"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "t"},
+	}, got)
+	require.Equal(t, Word, ts[2].Type, "receiver unchanged")
+
+	all := ts.ReplaceType(Whitespace, Comment, nil)
+	require.Equal(t, ts.String(), all.String())
+	for i, tok := range all {
+		if ts[i].Type == Whitespace {
+			require.Equal(t, Comment, tok.Type)
+		} else {
+			require.Equal(t, ts[i].Type, tok.Type)
+		}
+	}
+}
+
+func TestMapComments(t *testing.T) {
+	ts := Tokenize("SELECT a -- first\n, b /* TODO: fix */ FROM t /*+ hint */", OracleConfig())
+	upper := ts.MapComments(strings.ToUpper)
+	require.Equal(t, "SELECT a -- FIRST\n, b /* TODO: FIX */ FROM t /*+ hint */", upper.String())
+	require.Equal(t, len(ts), len(upper))
+
+	dropped := ts.MapComments(func(text string) string {
+		if strings.Contains(text, "TODO") {
+			return ""
+		}
+		return text
+	})
+	require.Equal(t, "SELECT a -- first\n, b  FROM t /*+ hint */", dropped.String())
+	require.Equal(t, len(ts)-2, len(dropped), "whitespace merged")
+	require.Equal(t, "SELECT a -- first\n, b /* TODO: fix */ FROM t /*+ hint */", ts.String(), "receiver unchanged")
+
+	dropAll := func(string) string { return "" }
+	ts = TokenizeMySQL("SELECT a/*TODO*/b, c-- x\nd")
+	dropped = ts.MapComments(dropAll)
+	require.Equal(t, "SELECT a b, c\nd", dropped.String())
+	require.True(t, TokenizeMySQL(dropped.String()).Equal(dropped), "tokens not joined")
+}
+
+func TestReplaceSequence(t *testing.T) {
+	is := func(tt TokenType, text string) TokenPredicate {
+		return func(t Token) bool {
+			return t.Type == tt && strings.EqualFold(t.Text, text)
+		}
+	}
+	now := []TokenPredicate{is(Word, "NOW"), is(Punctuation, "("), is(Punctuation, ")")}
+	currentTimestamp := func([]Token) []Token {
+		return []Token{{Type: Word, Text: "CURRENT_TIMESTAMP"}}
+	}
+	c := MySQLConfig().WithSeparatePunctuation()
+	ts := Tokenize("SELECT NOW(), now(),NOW(1), 'NOW()' FROM t", c)
+	got := ts.ReplaceSequence(now, currentTimestamp)
+	require.Equal(t, "SELECT CURRENT_TIMESTAMP, CURRENT_TIMESTAMP,NOW(1), 'NOW()' FROM t", got.String())
+	require.Equal(t, "SELECT NOW(), now(),NOW(1), 'NOW()' FROM t", ts.String(), "receiver unchanged")
+
+	var runs []string
+	ts.ReplaceSequence(now, func(run []Token) []Token {
+		runs = append(runs, Tokens(run).String())
+		return run
+	})
+	require.Equal(t, []string{"NOW()", "now()"}, runs)
+
+	words := []TokenPredicate{is(Word, "a"), is(Word, "a")}
+	aaa := Tokens{{Type: Word, Text: "a"}, {Type: Word, Text: "a"}, {Type: Word, Text: "a"}}
+	require.Equal(t, Tokens{{Type: Word, Text: "b"}, {Type: Word, Text: "a"}}, aaa.ReplaceSequence(words, func([]Token) []Token {
+		return []Token{{Type: Word, Text: "b"}}
+	}), "non-overlapping")
+	require.Equal(t, ts, ts.ReplaceSequence(nil, currentTimestamp))
+}
+
+func TestComments(t *testing.T) {
+	ts := TokenizeMySQL("SELECT a -- a\n, b /* b */ FROM t # c\n")
+	require.Equal(t, []CommentInfo{
+		{Index: 4, Style: "line", Text: "-- a\n"},
+		{Index: 9, Style: "block", Text: "/* b */"},
+		{Index: 15, Style: "line", Text: "# c\n"},
+	}, ts.Comments())
+	for _, c := range ts.Comments() {
+		require.Equal(t, c.Text, ts[c.Index].Text)
+	}
+
+	c := InformixConfig()
+	require.Equal(t, []CommentInfo{
+		{Index: 0, Style: "block", Text: "{ x }"},
+	}, Tokenize("{ x } SELECT 1", c).Comments())
+	require.Nil(t, TokenizeMySQL("SELECT '-- a'").Comments())
+}
+
+func TestBetween(t *testing.T) {
+	ts := TokenizeMySQL("SELECT a FROM t1 JOIN t2 WHERE x = 'where' AND y FROM")
+	require.Equal(t, " t1 JOIN t2 ", ts.Between("from", "WHERE").String())
+	require.Equal(t, "t1 JOIN t2", ts.Between("FROM", "where").TrimSpace().String())
+	require.Equal(t, " x = 'where' AND y ", ts.Between("where", "from").String())
+	require.Nil(t, ts.Between("FROM", "GROUP"))
+	require.Nil(t, ts.Between("HAVING", "WHERE"))
+	require.Nil(t, ts.Between("WHERE", "SELECT"), "end must come after start")
+	require.Equal(t, Tokens{{Type: Whitespace, Text: " "}}, TokenizeMySQL("SELECT FROM").Between("SELECT", "FROM"))
+}
+
+func TestMySQLVersionGates(t *testing.T) {
+	cases := []struct {
+		input string
+		want  []int
+	}{
+		{input: "SELECT /*!80000 SQL_NO_CACHE */ a FROM t", want: []int{80000}},
+		{input: "SELECT /*! STRAIGHT_JOIN */ a FROM t", want: []int{0}},
+		{input: "/*!40101 SET x=1 *//*!50700 SET y=2 */ -- /*!99999 */\n", want: []int{40101, 50700}},
+		{input: "SELECT '/*!80000 */', /* !80000 */ 1", want: nil},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.want, TokenizeMySQL(tc.input).MySQLVersionGates(), tc.input)
+	}
+}
+
+func TestRemoveHints(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "SELECT /*+ FULL(t) */ a /* regular */ FROM t",
+			want:  "SELECT  a /* regular */ FROM t",
+		},
+		{
+			input: "SELECT/*+ INDEX(t i) */a, b --+ x\n-- regular\nFROM t",
+			want:  "SELECT a, b -- regular\nFROM t",
+		},
+		{
+			input: "SELECT a--+ x\nFROM t /*+ end */",
+			want:  "SELECT a\nFROM t ",
+		},
+	}
+	for _, tc := range cases {
+		for _, config := range []Config{OracleConfig(), MySQLConfig()} {
+			ts := Tokenize(tc.input, config)
+			got := ts.RemoveHints()
+			require.Equal(t, tc.want, got.String(), tc.input)
+			require.True(t, Tokenize(got.String(), config).Equal(got), tc.input)
+			require.Equal(t, tc.input, ts.String(), "receiver unchanged")
+		}
+	}
+
+	ts := Tokenize("SELECT /* a *//*+ a /* b */ c */ 1 /* d /* e */ f */", PostgreSQLConfig())
+	require.Equal(t, "SELECT /* a */ 1 /* d /* e */ f */", ts.RemoveHints().String())
+	require.Equal(t, []string{"/* a /* b */", "/* c */"}, splitComments("/* a /* b *//* c */"))
+}
+
+func TestEqualAndHash(t *testing.T) {
+	ts := TokenizeMySQL("SELECT a, 'b' FROM t -- c\n")
+	c := ts.Copy()
+	c[2].SetMeta("column", true)
+	require.True(t, ts.Equal(c))
+	require.Equal(t, ts.Hash(), c.Hash())
+
+	different := []Tokens{
+		TokenizeMySQL("SELECT a, 'b' FROM t -- d\n"),
+		TokenizeMySQL("SELECT a, b FROM t -- c\n"),
+		TokenizeMySQL("SELECT a, 'b' FROM t"),
+		{{Type: Word, Text: "ab"}, {Type: Word, Text: "c"}},
+		{{Type: Word, Text: "a"}, {Type: Word, Text: "bc"}},
+		{{Type: Identifier, Text: "a"}, {Type: Word, Text: "bc"}},
+		{},
+	}
+	hashes := map[uint64]int{ts.Hash(): -1}
+	for i, other := range different {
+		require.False(t, ts.Equal(other), other.String())
+		prior, dup := hashes[other.Hash()]
+		require.False(t, dup, "%d collides with %d", i, prior)
+		hashes[other.Hash()] = i
+	}
+	require.True(t, Tokens{}.Equal(nil))
+	require.Equal(t, Tokens{}.Hash(), Tokens(nil).Hash())
+}
+
+func TestSemanticEqual(t *testing.T) {
+	cases := []struct {
+		a     string
+		b     string
+		equal bool
+	}{
+		{a: "SELECT  1", b: "SELECT\n1", equal: true},
+		{a: "SELECT 1", b: "SELECT 2", equal: false},
+		{a: "SELECT a /* x */ FROM t -- c\n", b: "SELECT a\nFROM t", equal: true},
+		{a: "INSERT INTO t VALUES (1),(2)", b: "INSERT INTO t VALUES (1), ( 2 )", equal: true},
+		{a: "SELECT 'a'", b: "SELECT 'b'", equal: false},
+		{a: "SELECT a", b: "select a", equal: false},
+		{a: "SELECT ab", b: "SELECT a b", equal: false},
+		{a: "\uFEFF SELECT 1;", b: "SELECT 1;", equal: true},
+	}
+	for _, tc := range cases {
+		a := TokenizeMySQL(tc.a)
+		b := TokenizeMySQL(tc.b)
+		require.Equal(t, tc.equal, a.SemanticEqual(b), "%q vs %q", tc.a, tc.b)
+		require.Equal(t, tc.equal, b.SemanticEqual(a), "%q vs %q", tc.b, tc.a)
+	}
+	require.True(t, Tokens{}.SemanticEqual(TokenizeMySQL(" -- c\n")))
+}
+
+func TestValidateBalanced(t *testing.T) {
+	cases := []struct {
+		input string
+		err   string
+	}{
+		{input: ""},
+		{input: "SELECT f((a), b[1]) FROM t WHERE x IN ('(', \")\") -- )"},
+		{input: "SELECT ((1", err: `unclosed '(' at token 2`},
+		{input: "SELECT (1))", err: `unbalanced ')' at token 4`},
+		{input: "SELECT a[1)]", err: `unbalanced ')' at token 5`},
+		{input: "SELECT 'abc", err: "unterminated Literal at token 2"},
+		{input: "SELECT 1 /* c", err: "unterminated Comment at token 4"},
+	}
+	for _, tc := range cases {
+		err := TokenizeMySQL(tc.input).ValidateBalanced()
+		if tc.err == "" {
+			require.NoError(t, err, tc.input)
+		} else {
+			require.EqualError(t, err, tc.err, tc.input)
+		}
+	}
+}
+
+func TestConcat(t *testing.T) {
+	a := TokenizeMySQL("SELECT a ")
+	b := TokenizeMySQL(" FROM t")
+	got := a.Concat(b)
+	require.Equal(t, Tokens{
+		{Type: Word, Text: "SELECT"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "a"},
+		{Type: Whitespace, Text: "  "},
+		{Type: Word, Text: "FROM"},
+		{Type: Whitespace, Text: " "},
+		{Type: Word, Text: "t"},
+	}, got)
+	require.Equal(t, TokenizeMySQL(got.String()), got)
+	require.Equal(t, "SELECT a ", a.String(), "receiver unchanged")
+	require.Equal(t, " FROM t", b.String(), "argument unchanged")
+
+	// Numbers are never merged
+	n := TokenizeMySQL("SELECT 1").Concat(Tokens{{Type: Number, Text: "2"}})
+	require.Len(t, n, 4)
+
+	require.Equal(t, b, Tokens{}.Concat(b))
+	require.Equal(t, a, a.Concat(nil))
+}
+
+func TestTokenString(t *testing.T) {
+	require.Equal(t, `Word("SELECT")`, Token{Type: Word, Text: "SELECT"}.String())
+	require.Equal(t, `Literal("'it\\'s\\n'")`, Token{Type: Literal, Text: `'it\'s\n'`}.String())
+	require.Equal(t, `Whitespace(" \n\t")`, Token{Type: Whitespace, Text: " \n\t"}.String())
+	require.Equal(t, `Other("\x00")`, Token{Type: Other, Text: "\x00"}.String())
+	require.Equal(t, `[Word("a") Punctuation(",")]`, fmt.Sprint([]Token{{Type: Word, Text: "a"}, {Type: Punctuation, Text: ","}}))
+	require.Equal(t, "a,", fmt.Sprint(Tokens{{Type: Word, Text: "a"}, {Type: Punctuation, Text: ","}}), "Tokens still prints as SQL")
+}
+
+func TestMatchParen(t *testing.T) {
+	ts := TokenizeMySQL("f(g(x), y)")
+	require.Equal(t, "(", ts[1].Text)
+	require.Equal(t, "(", ts[3].Text)
+	closeIndex, ok := ts.MatchParen(1)
+	require.True(t, ok)
+	require.Equal(t, 8, closeIndex)
+	require.Equal(t, ")", ts[closeIndex].Text)
+	closeIndex, ok = ts.MatchParen(3)
+	require.True(t, ok)
+	require.Equal(t, 5, closeIndex)
+	require.Equal(t, "),", ts[closeIndex].Text)
+
+	_, ok = ts.MatchParen(0)
+	require.False(t, ok, "not punctuation")
+	_, ok = ts.MatchParen(5)
+	require.False(t, ok, "no (")
+	_, ok = ts.MatchParen(-1)
+	require.False(t, ok)
+	_, ok = ts.MatchParen(len(ts))
+	require.False(t, ok)
+
+	ts = TokenizeMySQL("((a) ')' /* ) */, (b)")
+	require.Equal(t, "((", ts[0].Text)
+	_, ok = ts.MatchParen(0)
+	require.False(t, ok, "outer ( is not closed")
+	ts = TokenizeMySQL("((a) ')' /* ) */, (b))")
+	closeIndex, ok = ts.MatchParen(0)
+	require.True(t, ok)
+	require.Equal(t, len(ts)-1, closeIndex)
+}
+
+func TestInsertAtAndPrepend(t *testing.T) {
+	ts := TokenizeMySQL("SELECT * FROM t ;")
+	where := TokenizeMySQL(" WHERE x = 1 ")
+	got := ts.InsertAt(len(ts)-1, where...)
+	require.Equal(t, "SELECT * FROM t  WHERE x = 1 ;", got.String())
+	require.Equal(t, TokenizeMySQL(got.String()), got, "merged at both seams")
+	require.Equal(t, "SELECT * FROM t ;", ts.String(), "receiver unchanged")
+
+	got = ts.Prepend(TokenizeMySQL("/* c */ ")...)
+	require.Equal(t, "/* c */ SELECT * FROM t ;", got.String())
+	require.Equal(t, TokenizeMySQL(got.String()), got)
+
+	require.Equal(t, ts, ts.InsertAt(0))
+	require.Equal(t, ts.Concat(where), ts.InsertAt(len(ts), where...))
+}
+
+func TestCollapseInLists(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "SELECT * FROM t WHERE a IN (1,2,3)",
+			want:  "SELECT * FROM t WHERE a IN (?)",
+		},
+		{
+			input: "SELECT * FROM t WHERE a in (?, ?) AND b IN ('x', NULL, -1);",
+			want:  "SELECT * FROM t WHERE a in (?) AND b IN (?);",
+		},
+		{
+			input: "SELECT * FROM t WHERE a IN /* c */ (1),b",
+			want:  "SELECT * FROM t WHERE a IN /* c */ (?),b",
+		},
+		{
+			input: "SELECT * FROM t WHERE a IN (SELECT id FROM t)",
+			want:  "SELECT * FROM t WHERE a IN (SELECT id FROM t)",
+		},
+		{
+			input: "SELECT * FROM t WHERE (a, b) IN ((1, 2), (3, 4))",
+			want:  "SELECT * FROM t WHERE (a, b) IN ((1, 2), (3, 4))",
+		},
+		{
+			input: "SELECT * FROM t WHERE a IN (1, 2",
+			want:  "SELECT * FROM t WHERE a IN (1, 2",
+		},
+		{
+			input: "SELECT in FROM t",
+			want:  "SELECT in FROM t",
+		},
+	}
+	for _, tc := range cases {
+		ts := TokenizeMySQL(tc.input)
+		got := ts.CollapseInLists()
+		require.Equal(t, tc.want, got.String(), tc.input)
+		require.Equal(t, tc.input, ts.String(), "receiver unchanged")
+	}
+	require.Equal(t,
+		TokenizeMySQL("SELECT 1 WHERE x IN (1)").CollapseInLists(),
+		TokenizeMySQL("SELECT 1 WHERE x IN (1, 2, 3, 4)").CollapseInLists())
+}
+
+func TestComplexity(t *testing.T) {
+	simple := TokenizeMySQL("SELECT 1")
+	busy := TokenizeMySQL(`
+		SELECT count(*), max(b.x)
+		FROM a
+		JOIN b ON a.id = b.id
+		LEFT JOIN (SELECT id FROM c WHERE c.x IN (1, 2)) AS c2 ON c2.id = a.id
+		WHERE a.y IN ( /* sub */ SELECT y FROM d)
+		UNION ALL
+		SELECT 1, 2`)
+	require.Equal(t, 0, simple.Complexity())
+	// 2 joins, 2 subqueries, 1 union, 2 function calls
+	require.Equal(t, 2+2*2+1+2, busy.Complexity())
+	require.Equal(t, 2, busy.ComplexityWith(ComplexityWeights{Join: 1}))
+	require.Equal(t, 2, busy.ComplexityWith(ComplexityWeights{FunctionCall: 1}))
+	require.Equal(t, 20, busy.ComplexityWith(ComplexityWeights{Subquery: 10}))
+	require.Less(t, simple.Complexity(), busy.Complexity())
+}
+
+func TestRenameIdentifiers(t *testing.T) {
+	mapping := map[string]string{
+		"old_table": "new_table",
+		"a`b":       "c`d",
+		`a\b`:       "x",
+	}
+	mySQLBackticks := MySQLConfig()
+	mySQLBackticks.NoticeBacktickIdentifiers = true
+	cases := []struct {
+		input  string
+		config Config
+		want   string
+		fold   string
+	}{
+		{
+			input:  "SELECT old_table.x FROM `old_table` JOIN s.OLD_TABLE, old_tables, 'old_table', `a``b`, `a\\b`",
+			config: mySQLBackticks,
+			want:   "SELECT new_table.x FROM `new_table` JOIN s.OLD_TABLE, old_tables, 'old_table', `c``d`, `x`",
+			fold:   "SELECT new_table.x FROM `new_table` JOIN s.new_table, old_tables, 'old_table', `c``d`, `x`",
+		},
+		{
+			input:  `SELECT * FROM [old_table], "old_table", [Old_Table]`,
+			config: SQLServerConfig(),
+			want:   `SELECT * FROM [new_table], "old_table", [Old_Table]`,
+			fold:   `SELECT * FROM [new_table], "old_table", [new_table]`,
+		},
+		{
+			input:  `SELECT * FROM "old_table" -- old_table`,
+			config: PostgreSQLConfig(),
+			want:   `SELECT * FROM "old_table" -- old_table`,
+			fold:   `SELECT * FROM "old_table" -- old_table`,
+		},
+		{
+			input:  `SELECT * FROM "old_table", "a"`,
+			config: ANSIConfig(),
+			want:   `SELECT * FROM "new_table", "a"`,
+			fold:   `SELECT * FROM "new_table", "a"`,
+		},
+	}
+	for _, tc := range cases {
+		ts := Tokenize(tc.input, tc.config)
+		require.Equal(t, tc.want, ts.RenameIdentifiers(mapping).String(), tc.input)
+		require.Equal(t, tc.fold, ts.RenameIdentifiersFold(mapping).String(), tc.input)
+		require.Equal(t, tc.input, ts.String(), "receiver unchanged")
+	}
+}
+
+func TestRenameIdentifiersFoldConflicts(t *testing.T) {
+	mapping := map[string]string{
+		"Tbl": "upper",
+		"tbl": "lower",
+		"TBL": "all",
+	}
+	ts := TokenizeMySQL("SELECT * FROM tbl, Tbl, TBL, tBL")
+	for i := 0; i < 20; i++ {
+		require.Equal(t, "SELECT * FROM lower, upper, all, all", ts.RenameIdentifiersFold(mapping).String())
+	}
+}
+
+func TestMeta(t *testing.T) {
+	ts := TokenizeMySQL("SELECT a FROM t")
+	for _, tok := range ts {
+		require.Nil(t, tok.Meta)
+	}
+	_, ok := ts[6].GetMeta("table")
+	require.False(t, ok)
+	ts[6].SetMeta("table", true)
+	ts[6].SetMeta("alias", "x")
+	v, ok := ts[6].GetMeta("table")
+	require.True(t, ok)
+	require.Equal(t, true, v)
+
+	c := ts.Copy()
+	require.Equal(t, ts, c)
+	c[6].SetMeta("table", false)
+	c[2].SetMeta("column", true)
+	v, _ = ts[6].GetMeta("table")
+	require.Equal(t, true, v, "copy is independent")
+	_, ok = ts[2].GetMeta("column")
+	require.False(t, ok, "copy is independent")
+	v, _ = c[6].GetMeta("alias")
+	require.Equal(t, "x", v)
+}
+
+func TestDumpString(t *testing.T) {
+	require.Equal(t, `Word("SELECT")
+Whitespace(" ")
+Literal("'a\nb'")
+Whitespace(" ")
+Literal("'c") unterminated
+`, DumpString(TokenizeMySQL("SELECT 'a\nb' 'c")))
+	require.Equal(t, "", DumpString(nil))
+}
+
+func TestDiff(t *testing.T) {
+	c := PostgreSQLConfig()
+	a := Tokenize("SELECT a->>'k' FROM t", c)
+	c.NoticePgOperators = false
+	b := Tokenize("SELECT a->>'k' FROM t", c)
+	require.Equal(t, ""+
+		` Word("SELECT")`+"\n"+
+		` Whitespace(" ")`+"\n"+
+		` Word("a")`+"\n"+
+		`-Operator("->>")`+"\n"+
+		`+Punctuation("->>")`+"\n"+
+		` Literal("'k'")`+"\n"+
+		` Whitespace(" ")`+"\n"+
+		` Word("FROM")`+"\n"+
+		` Whitespace(" ")`+"\n"+
+		` Word("t")`+"\n", a.Diff(b))
+	require.Equal(t, "", a.Diff(a.Copy()))
+	require.Equal(t, "+Word(\"x\")\n", Tokens{}.Diff(Tokens{{Type: Word, Text: "x"}}))
+}
+
+func TestCanonicalize(t *testing.T) {
+	cases := []struct {
+		name  string
+		input Tokens
+		want  string
+	}{
+		{
+			name: "words",
+			input: Tokens{
+				{Type: Word, Text: "SELECT"},
+				{Type: Word, Text: "a"},
+				{Type: Punctuation, Text: ","},
+				{Type: Number, Text: "1"},
+				{Type: Number, Text: "2"},
+			},
+			want: "SELECT a,1 2",
+		},
+		{
+			name: "punctuation",
+			input: Tokens{
+				{Type: Word, Text: "a"},
+				{Type: Punctuation, Text: "-"},
+				{Type: Punctuation, Text: "-"},
+				{Type: Literal, Text: "'x'"},
+				{Type: Literal, Text: "'y'"},
+			},
+			want: "a- -'x' 'y'",
+		},
+		{
+			name: "comment",
+			input: Tokens{
+				{Type: Comment, Text: "-- c"},
+				{Type: Word, Text: "x"},
+				{Type: Comment, Text: "/* d */"},
+				{Type: Word, Text: "y"},
+			},
+			want: "-- c\nx/* d */y",
+		},
+		{
+			name: "resplit",
+			input: Tokens{
+				{Type: Word, Text: "a b"},
+			},
+			want: "a b",
+		},
+		{
+			name:  "canonical",
+			input: TokenizeMySQL("SELECT a, 'b' FROM t -- c\n"),
+			want:  "SELECT a, 'b' FROM t -- c\n",
+		},
+	}
+	for _, tc := range cases {
+		got := tc.input.Canonicalize(MySQLConfig())
+		require.Equal(t, tc.want, got.String(), tc.name)
+		require.True(t, TokenizeMySQL(got.String()).Equal(got), tc.name)
+	}
+}
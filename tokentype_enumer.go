@@ -8,9 +8,9 @@ import (
 	"fmt"
 )
 
-const _TokenTypeName = "CommentWhitespaceQuestionMarkAtSignDollarNumberColonWordLiteralIdentifierAtWordNumberSemicolonPunctuationWordOther"
+const _TokenTypeName = "CommentWhitespaceQuestionMarkAtSignDollarNumberColonWordLiteralIdentifierAtWordNumberSemicolonPunctuationWordOtherOperatorBOMHintTypedLiteralCopyDataSystemVariableColonNumberSubstitutionVariableTruncatedMoney"
 
-var _TokenTypeIndex = [...]uint8{0, 7, 17, 29, 35, 47, 56, 63, 73, 79, 85, 94, 105, 109, 114}
+var _TokenTypeIndex = [...]uint8{0, 7, 17, 29, 35, 47, 56, 63, 73, 79, 85, 94, 105, 109, 114, 122, 125, 129, 141, 149, 163, 174, 194, 203, 208}
 
 func (i TokenType) String() string {
 	if i < 0 || i >= TokenType(len(_TokenTypeIndex)-1) {
@@ -19,7 +19,7 @@ func (i TokenType) String() string {
 	return _TokenTypeName[_TokenTypeIndex[i]:_TokenTypeIndex[i+1]]
 }
 
-var _TokenTypeValues = []TokenType{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13}
+var _TokenTypeValues = []TokenType{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23}
 
 var _TokenTypeNameToValueMap = map[string]TokenType{
 	_TokenTypeName[0:7]:     0,
@@ -36,6 +36,16 @@ var _TokenTypeNameToValueMap = map[string]TokenType{
 	_TokenTypeName[94:105]:  11,
 	_TokenTypeName[105:109]: 12,
 	_TokenTypeName[109:114]: 13,
+	_TokenTypeName[114:122]: 14,
+	_TokenTypeName[122:125]: 15,
+	_TokenTypeName[125:129]: 16,
+	_TokenTypeName[129:141]: 17,
+	_TokenTypeName[141:149]: 18,
+	_TokenTypeName[149:163]: 19,
+	_TokenTypeName[163:174]: 20,
+	_TokenTypeName[174:194]: 21,
+	_TokenTypeName[194:203]: 22,
+	_TokenTypeName[203:208]: 23,
 }
 
 // TokenTypeString retrieves an enum value from the enum constants string name.
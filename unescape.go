@@ -0,0 +1,148 @@
+package sqltoken
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unescape decodes the text of a Literal token following the quoting
+// rules of cfg, which should be the Config that produced the token:
+// any prefix (N, _charset, U&, q) is removed along with the quotes,
+// doubled quotes become one quote, and, unless NoBackslashEscapes is
+// set, backslash escapes are decoded as MySQL does: \% and \_ keep
+// their backslash since they are only escapes in LIKE patterns.
+// With StandardConformingStrings, only E'...' strings have backslash
+// escapes.  Dollar quoted and q'...' strings have no escapes.  The
+// \XXXX escapes in U&'...' strings are left alone.  An error is
+// returned if lit is not a complete string literal.
+func Unescape(lit string, cfg Config) (string, error) {
+	if strings.HasPrefix(lit, "$") {
+		end := strings.IndexByte(lit[1:], '$')
+		if end == -1 {
+			return "", fmt.Errorf("not a string literal: %s", lit)
+		}
+		tag := lit[:end+2]
+		if len(lit) < 2*len(tag) || !strings.HasSuffix(lit, tag) {
+			return "", fmt.Errorf("unterminated string literal: %s", lit)
+		}
+		return lit[len(tag) : len(lit)-len(tag)], nil
+	}
+	start := strings.IndexAny(lit, `'"`)
+	if start == -1 {
+		return "", fmt.Errorf("not a string literal: %s", lit)
+	}
+	prefix := lit[:start]
+	quote := lit[start]
+	body := lit[start+1:]
+	if len(body) == 0 || body[len(body)-1] != quote {
+		return "", fmt.Errorf("unterminated string literal: %s", lit)
+	}
+	if cfg.NoticeDeliminatedStrings && quote == '\'' && strings.HasSuffix(strings.ToLower(prefix), "q") {
+		return unescapeDeliminated(lit, body)
+	}
+	body = body[:len(body)-1]
+	backslash := !cfg.NoBackslashEscapes && !strings.HasSuffix(prefix, "&")
+	if cfg.StandardConformingStrings {
+		backslash = strings.EqualFold(prefix, "e")
+	}
+	var b strings.Builder
+	b.Grow(len(body))
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == quote:
+			// The lexer only ends a literal at a quote that is
+			// followed by another quote if they are doubled
+			if i+1 >= len(body) || body[i+1] != quote {
+				return "", fmt.Errorf("unterminated string literal: %s", lit)
+			}
+			i++
+		case c == '\\' && backslash:
+			if i+1 >= len(body) {
+				return "", fmt.Errorf("unterminated string literal: %s", lit)
+			}
+			i++
+			c = body[i]
+			if c == '%' || c == '_' {
+				b.WriteByte('\\')
+			} else {
+				c = unescapeByte(c)
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
+
+// unescapeByte returns the byte that a backslash followed by c stands
+// for.  Characters without a special meaning stand for themselves.
+func unescapeByte(c byte) byte {
+	switch c {
+	case '0':
+		return 0
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'b':
+		return '\b'
+	case 'Z':
+		return 0x1a
+	}
+	return c
+}
+
+// unescapeDeliminated returns the text between the delimiters of
+// q'[...]' where body is everything after the opening quote
+func unescapeDeliminated(lit string, body string) (string, error) {
+	if len(body) < 3 {
+		return "", fmt.Errorf("unterminated string literal: %s", lit)
+	}
+	var closer string
+	switch body[0] {
+	case '(':
+		closer = ")"
+	case '<':
+		closer = ">"
+	case '[':
+		closer = "]"
+	case '{':
+		closer = "}"
+	}
+	open := body[:1]
+	if closer == "" {
+		for _, r := range body {
+			open = string(r)
+			break
+		}
+		closer = open
+	}
+	if len(body) < len(open)+len(closer)+1 || !strings.HasSuffix(body, closer+"'") {
+		return "", fmt.Errorf("unterminated string literal: %s", lit)
+	}
+	return body[len(open) : len(body)-len(closer)-1], nil
+}
+
+// StringLiterals returns the text of each Literal token in ts.  If
+// unescape is true, the quotes are removed and escapes are decoded
+// according to cfg, which should be the Config that produced ts.
+// Literals that cannot be decoded, like Unterminated ones, are
+// returned as-is.
+func (ts Tokens) StringLiterals(cfg Config, unescape bool) []string {
+	var r []string
+	for _, t := range ts {
+		if t.Type != Literal {
+			continue
+		}
+		text := t.Text
+		if unescape {
+			if u, err := Unescape(text, cfg); err == nil {
+				text = u
+			}
+		}
+		r = append(r, text)
+	}
+	return r
+}
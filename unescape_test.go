@@ -0,0 +1,88 @@
+package sqltoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringLiterals(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   Config
+		input    string
+		raw      []string
+		unescape []string
+	}{
+		{
+			name:     "mysql",
+			config:   MySQLConfig(),
+			input:    `SELECT 'it''s', 'a\nb', "x""y", _utf8'z' FROM t`,
+			raw:      []string{`'it''s'`, `'a\nb'`, `"x""y"`, `_utf8'z'`},
+			unescape: []string{`it's`, "a\nb", `x"y`, `z`},
+		},
+		{
+			name:     "ansi",
+			config:   ANSIConfig(),
+			input:    `SELECT 'it''s', 'a\nb', "x""y" FROM t`,
+			raw:      []string{`'it''s'`, `'a\nb'`},
+			unescape: []string{`it's`, `a\nb`},
+		},
+		{
+			name:     "postgres",
+			config:   PostgreSQLConfig(),
+			input:    `SELECT $$it's$$, $q$a$$b$q$, U&'d\0061t'`,
+			raw:      []string{`$$it's$$`, `$q$a$$b$q$`, `U&'d\0061t'`},
+			unescape: []string{`it's`, `a$$b`, `d\0061t`},
+		},
+		{
+			name:     "oracle",
+			config:   OracleConfig(),
+			input:    `SELECT q'[it's]', Nq'ǝaǝ', n'b''c', 'unterminated`,
+			raw:      []string{`q'[it's]'`, `Nq'ǝaǝ'`, `n'b''c'`, `'unterminated`},
+			unescape: []string{`it's`, `a`, `b'c`, `'unterminated`},
+		},
+	}
+	for _, tc := range cases {
+		ts := Tokenize(tc.input, tc.config)
+		require.Equal(t, tc.raw, ts.StringLiterals(tc.config, false), tc.name+" raw")
+		require.Equal(t, tc.unescape, ts.StringLiterals(tc.config, true), tc.name+" unescaped")
+	}
+	require.Nil(t, TokenizeMySQL("SELECT 1").StringLiterals(MySQLConfig(), true))
+}
+
+func TestUnescape(t *testing.T) {
+	cases := []struct {
+		lit    string
+		config Config
+		want   string
+		err    bool
+	}{
+		{lit: `'a\nb'`, config: MySQLConfig(), want: "a\nb"},
+		{lit: `'it''s'`, config: MySQLConfig(), want: "it's"},
+		{lit: `'it\'s'`, config: MySQLConfig(), want: "it's"},
+		{lit: `'\%'`, config: MySQLConfig(), want: `\%`},
+		{lit: `'\_\\\x'`, config: MySQLConfig(), want: `\_\x`},
+		{lit: `'\0\b\r\t\Z\"'`, config: MySQLConfig(), want: "\x00\b\r\t\x1a\""},
+		{lit: `"a\"b"`, config: MySQLConfig(), want: `a"b`},
+		{lit: `'a\nb'`, config: ANSIConfig(), want: `a\nb`},
+		{lit: `'it''s'`, config: ANSIConfig(), want: "it's"},
+		{lit: `'a\'`, config: PostgreSQLConfig(), want: `a\`},
+		{lit: `E'a\''`, config: PostgreSQLConfig(), want: `a'`},
+		{lit: `e'\n'`, config: PostgreSQLConfig(), want: "\n"},
+		{lit: `E'a''\'x'`, config: PostgreSQLConfig(), want: "a''x"},
+		{lit: `'abc`, config: MySQLConfig(), err: true},
+		{lit: `'abc\'`, config: MySQLConfig(), err: true},
+		{lit: `abc`, config: MySQLConfig(), err: true},
+		{lit: `$tag$abc`, config: PostgreSQLConfig(), err: true},
+	}
+	for _, tc := range cases {
+		got, err := Unescape(tc.lit, tc.config)
+		if tc.err {
+			require.Error(t, err, tc.lit)
+			continue
+		}
+		require.NoError(t, err, tc.lit)
+		require.Equal(t, tc.want, got, tc.lit)
+	}
+}